@@ -0,0 +1,63 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package acctest
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/mulei1288/terraform-provider-bingocloud/internal/provider"
+)
+
+// ProtoV6ProviderFactories 用于各服务包验收测试的 Provider 工厂
+var ProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"bingocloud": providerserver.NewProtocol6WithError(provider.New("test")()),
+}
+
+// PreCheck 验收测试前置检查，确保必需的环境变量已设置
+// 每项配置都可以使用 BINGOCLOUD_* 或 AWS_* 环境变量提供
+func PreCheck(t *testing.T) {
+	// 检查必需的环境变量
+	for _, keys := range [][2]string{
+		{"BINGOCLOUD_ENDPOINT", "AWS_ENDPOINT"},
+		{"BINGOCLOUD_ACCESS_KEY", "AWS_ACCESS_KEY_ID"},
+		{"BINGOCLOUD_SECRET_KEY", "AWS_SECRET_ACCESS_KEY"},
+	} {
+		if os.Getenv(keys[0]) == "" && os.Getenv(keys[1]) == "" {
+			t.Fatalf("%s（或 %s）环境变量必须设置用于验收测试", keys[0], keys[1])
+		}
+	}
+}
+
+// ProviderConfig 返回基础的 Provider 配置
+func ProviderConfig() string {
+	return `
+provider "bingocloud" {
+  # 配置通过环境变量提供：
+  # BINGOCLOUD_ENDPOINT（或 AWS_ENDPOINT）
+  # BINGOCLOUD_ACCESS_KEY（或 AWS_ACCESS_KEY_ID）
+  # BINGOCLOUD_SECRET_KEY（或 AWS_SECRET_ACCESS_KEY）
+}
+`
+}
+
+// ProviderConfigDefaultTags 返回带有 default_tags 和 ignore_tags 的 Provider 配置
+func ProviderConfigDefaultTags(key, value, ignoreKeyPrefix string) string {
+	return fmt.Sprintf(`
+provider "bingocloud" {
+  default_tags {
+    tags = {
+      %[1]q = %[2]q
+    }
+  }
+
+  ignore_tags {
+    key_prefixes = [%[3]q]
+  }
+}
+`, key, value, ignoreKeyPrefix)
+}
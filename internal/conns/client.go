@@ -4,13 +4,12 @@
 package conns
 
 import (
-	"crypto/tls"
-	"fmt"
-	"net/http"
+	"context"
 	"sync"
 
+	tftags "github.com/mulei1288/terraform-provider-bingocloud/internal/tags"
 	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws"
-	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/credentials"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/request"
 	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/session"
 	"gitlab.bingosoft.net/bingokube/aws-sdk-go/service/ec2"
 )
@@ -20,39 +19,71 @@ type BingoCloudClient struct {
 	Config  *aws.Config      // AWS SDK 配置
 	Session *session.Session // AWS Session
 
+	// TerminateOnCreateFailure 创建失败时是否自动终止已创建的实例
+	TerminateOnCreateFailure bool
+
+	// 标签配置：provider 级别的默认标签和忽略标签
+	DefaultTagsConfig *tftags.DefaultConfig
+	IgnoreTagsConfig  *tftags.IgnoreConfig
+
 	// 服务客户端缓存（线程安全）
 	ec2Client     *ec2.EC2
 	ec2ClientLock sync.RWMutex
 }
 
 // NewBingoCloudClient 创建新的 BingoCloud 客户端
-func NewBingoCloudClient(endpoint, accessKey, secretKey, region string, insecureSkipTLS bool) (*BingoCloudClient, error) {
+func NewBingoCloudClient(ctx context.Context, c *Config) (*BingoCloudClient, error) {
 	// 配置 AWS SDK
 	cfg := &aws.Config{
-		Endpoint:         aws.String(endpoint),
-		Region:           aws.String(region),
-		Credentials:      credentials.NewStaticCredentials(accessKey, secretKey, ""),
+		Endpoint:         aws.String(c.Endpoint),
 		DisableSSL:       aws.Bool(false),
 		S3ForcePathStyle: aws.Bool(true),
 	}
+	if c.Region != "" {
+		cfg.Region = aws.String(c.Region)
+	}
 
-	// 如果需要跳过 TLS 验证（私有云环境常用）
-	if insecureSkipTLS {
-		cfg.HTTPClient = &http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-			},
-		}
+	// TLS、代理和连接参数都在同一个 transport 上配置
+	httpClient, err := c.httpClient()
+	if err != nil {
+		return nil, err
+	}
+	cfg.HTTPClient = httpClient
+
+	// 限流和临时错误按指数退避重试
+	maxRetries := DefaultMaxRetries
+	if c.MaxRetries != nil {
+		maxRetries = *c.MaxRetries
 	}
+	retryer := newRetryer(maxRetries, c.RetryMode)
+	request.WithRetryer(cfg, retryer)
 
-	// 创建 session
-	sess, err := session.NewSession(cfg)
+	// 创建 session，凭证按静态密钥、共享凭证文件的顺序解析
+	sess, err := c.newSession(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("创建 AWS session 失败: %w", err)
+		return nil, err
 	}
 
+	retryer.addHandlers(&sess.Handlers)
+
+	// 未配置区域且共享配置文件中也没有时使用默认区域
+	if aws.StringValue(sess.Config.Region) == "" {
+		sess.Config.Region = aws.String(DefaultRegion)
+	}
+
+	// 使用上面的凭证扮演角色，之后的请求都使用临时凭证
+	if c.AssumeRole != nil {
+		sess, err = c.assumeRole(ctx, sess)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 在调试日志中记录最终使用的凭证对应的身份
+	logCallerIdentity(ctx, sess)
+
 	return &BingoCloudClient{
-		Config:  cfg,
+		Config:  sess.Config,
 		Session: sess,
 	}, nil
 }
@@ -0,0 +1,262 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package conns
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/client"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/credentials"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/credentials/stscreds"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/defaults"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/request"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/session"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/service/sts"
+)
+
+// DefaultRegion 未配置区域时使用的默认区域
+const DefaultRegion = "default"
+
+// DefaultAssumeRoleSessionName 扮演角色时默认的会话名称
+const DefaultAssumeRoleSessionName = "terraform-provider-bingocloud"
+
+// callerIdentityTimeout 查询调用者身份的超时时间，查询只用于记录日志，不应拖慢 Configure
+const callerIdentityTimeout = 10 * time.Second
+
+// Config 创建 BingoCloud 客户端所需的配置，由 provider 从 HCL 和环境变量解析得到
+type Config struct {
+	Endpoint        string
+	AccessKey       string
+	SecretKey       string
+	Token           string
+	Region          string
+	InsecureSkipTLS bool
+
+	// 自定义 CA 和双向 TLS 客户端证书，值可以是文件路径或 PEM 内容
+	CABundle          string
+	ClientCertificate string
+	ClientKey         string
+
+	// 代理和连接参数，零值表示使用 http.DefaultTransport 的默认值
+	HTTPProxy      string
+	NoProxy        string
+	ConnectTimeout time.Duration
+	RequestTimeout time.Duration
+	MaxIdleConns   int
+
+	// 重试配置，MaxRetries 为 nil 时使用 DefaultMaxRetries，RetryMode 为空时使用 standard
+	MaxRetries *int
+	RetryMode  string
+
+	// 共享凭证文件和命名 profile，未配置静态密钥时使用
+	Profile                string
+	SharedCredentialsFiles []string
+	SharedConfigFiles      []string
+
+	// 扮演角色配置，为空时直接使用上面解析出的凭证
+	AssumeRole *AssumeRoleConfig
+
+	// DisableAWSEnv 为 true 时不从 AWS_* 环境变量读取 profile、共享文件路径、区域和 TLS 证书。
+	// 使用静态密钥时不经过 SDK 的环境变量加载，不会读取任何 AWS_* 环境变量；
+	// 通过 profile 加载凭证时 SDK 仍会打开 AWS_CA_BUNDLE 等证书文件，但只作用于加载凭证用的 HTTP 客户端副本
+	DisableAWSEnv bool
+}
+
+// AssumeRoleConfig 通过 STS 扮演角色的配置
+type AssumeRoleConfig struct {
+	RoleARN     string
+	SessionName string
+	Duration    time.Duration
+	ExternalID  string
+}
+
+// newSession 创建 session
+// 配置了静态密钥时直接使用；否则通过 SDK 的共享配置加载 profile，
+// 从共享凭证文件和共享配置文件中解析凭证和区域
+func (c *Config) newSession(cfg *aws.Config) (*session.Session, error) {
+	// SDK 会在区域为空时读取 AWS_REGION，禁用 AWS_* 环境变量时直接使用默认区域
+	if c.DisableAWSEnv && cfg.Region == nil {
+		cfg.Region = aws.String(DefaultRegion)
+	}
+
+	if c.AccessKey != "" && c.SecretKey != "" {
+		cfg.Credentials = credentials.NewStaticCredentials(c.AccessKey, c.SecretKey, c.Token)
+
+		if c.DisableAWSEnv {
+			return newExplicitSession(cfg), nil
+		}
+
+		sess, err := session.NewSession(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("创建 AWS session 失败: %w", err)
+		}
+		return sess, nil
+	}
+
+	opts := session.Options{
+		Config:            *cfg,
+		Profile:           c.Profile,
+		SharedConfigState: session.SharedConfigEnable,
+	}
+
+	// 按 SDK 的约定先加载配置文件，再加载凭证文件，后加载的文件优先
+	configFiles, credentialsFiles := c.SharedConfigFiles, c.SharedCredentialsFiles
+	if c.DisableAWSEnv {
+		// 显式指定 profile 和文件路径，避免 SDK 读取 AWS_PROFILE、AWS_ACCESS_KEY_ID 和共享文件路径的环境变量
+		if opts.Profile == "" {
+			opts.Profile = session.DefaultSharedConfigProfile
+		}
+		if len(configFiles) == 0 {
+			configFiles = []string{defaults.SharedConfigFilename()}
+		}
+		if len(credentialsFiles) == 0 {
+			credentialsFiles = []string{defaults.SharedCredentialsFilename()}
+		}
+	}
+	if len(configFiles) > 0 || len(credentialsFiles) > 0 {
+		files, err := expandPaths(append(append([]string{}, configFiles...), credentialsFiles...))
+		if err != nil {
+			return nil, err
+		}
+		opts.SharedConfigFiles = files
+	}
+
+	// SDK 加载共享配置时会按 AWS_CA_BUNDLE 和 AWS_SDK_GO_CLIENT_TLS_* 修改 HTTP 客户端的 TLS 配置，
+	// 禁用 AWS_* 环境变量时加载使用 HTTP 客户端的副本，之后的请求使用不经过 SDK 加载的 session
+	if c.DisableAWSEnv {
+		opts.Config.HTTPClient = copyHTTPClient(cfg.HTTPClient)
+	}
+
+	sess, err := session.NewSessionWithOptions(opts)
+	if err != nil {
+		return nil, fmt.Errorf("加载 profile %q 失败: %w", c.profileName(), err)
+	}
+
+	// 提前解析凭证，避免在第一次调用 API 时才发现凭证缺失
+	if _, err := sess.Config.Credentials.Get(); err != nil {
+		return nil, fmt.Errorf("无法从 profile %q 解析凭证: %w", c.profileName(), err)
+	}
+
+	if c.DisableAWSEnv {
+		cfg.Credentials = sess.Config.Credentials
+		return newExplicitSession(cfg), nil
+	}
+
+	return sess, nil
+}
+
+// newExplicitSession 使用 SDK 的默认配置和 handler 直接构建 session，
+// 不经过 SDK 的环境变量和共享配置加载，cfg 中必须显式设置区域和凭证
+func newExplicitSession(cfg *aws.Config) *session.Session {
+	base := &session.Session{
+		Config:   defaults.Config(),
+		Handlers: defaults.Handlers(),
+	}
+	return base.Copy(cfg)
+}
+
+// copyHTTPClient 返回 HTTP 客户端的副本，transport 为 *http.Transport 时一并复制
+func copyHTTPClient(client *http.Client) *http.Client {
+	if client == nil {
+		return &http.Client{}
+	}
+
+	cp := *client
+	if transport, ok := client.Transport.(*http.Transport); ok {
+		cp.Transport = transport.Clone()
+	}
+	return &cp
+}
+
+// assumeRole 使用基础凭证通过 STS 兼容接口扮演角色，返回使用临时凭证的 session
+// STS 请求与其它服务使用相同的 endpoint，临时凭证过期前会自动重新扮演角色刷新
+func (c *Config) assumeRole(ctx context.Context, base *session.Session) (*session.Session, error) {
+	ar := c.AssumeRole
+
+	sessionName := ar.SessionName
+	if sessionName == "" {
+		sessionName = DefaultAssumeRoleSessionName
+	}
+
+	creds := stscreds.NewCredentials(base, ar.RoleARN, func(p *stscreds.AssumeRoleProvider) {
+		p.RoleSessionName = sessionName
+		if ar.Duration > 0 {
+			p.Duration = ar.Duration
+		}
+		if ar.ExternalID != "" {
+			p.ExternalID = aws.String(ar.ExternalID)
+		}
+	})
+
+	tflog.Debug(ctx, "扮演角色", map[string]interface{}{
+		"role_arn":     ar.RoleARN,
+		"session_name": sessionName,
+		"duration":     ar.Duration.String(),
+	})
+
+	// 提前获取一次临时凭证，配置错误时在 Configure 阶段报错
+	if _, err := creds.GetWithContext(ctx); err != nil {
+		return nil, fmt.Errorf("扮演角色 %s 失败: %w", ar.RoleARN, err)
+	}
+
+	return base.Copy(&aws.Config{Credentials: creds}), nil
+}
+
+// logCallerIdentity 在调试日志中记录当前凭证对应的身份，查询失败不影响后续操作
+// 查询不重试并使用较短的超时时间，STS 接口不可用时不会等待重试退避
+func logCallerIdentity(ctx context.Context, sess *session.Session) {
+	ctx, cancel := context.WithTimeout(ctx, callerIdentityTimeout)
+	defer cancel()
+
+	output, err := sts.New(sess).GetCallerIdentityWithContext(ctx, &sts.GetCallerIdentityInput{}, func(r *request.Request) {
+		r.Retryer = client.NoOpRetryer{}
+	})
+	if err != nil {
+		tflog.Debug(ctx, "无法查询调用者身份", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	tflog.Debug(ctx, "当前调用者身份", map[string]interface{}{
+		"arn":     aws.StringValue(output.Arn),
+		"account": aws.StringValue(output.Account),
+		"user_id": aws.StringValue(output.UserId),
+	})
+}
+
+// profileName 返回实际使用的 profile 名称，用于错误信息
+func (c *Config) profileName() string {
+	if c.Profile != "" {
+		return c.Profile
+	}
+	if v := os.Getenv("AWS_PROFILE"); v != "" && !c.DisableAWSEnv {
+		return v
+	}
+	return session.DefaultSharedConfigProfile
+}
+
+// expandPaths 展开路径中的 ~ 为用户主目录
+func expandPaths(paths []string) ([]string, error) {
+	result := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if p == "~" || strings.HasPrefix(p, "~/") {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return nil, fmt.Errorf("无法展开路径 %s: %w", p, err)
+			}
+			p = filepath.Join(home, strings.TrimPrefix(p, "~"))
+		}
+		result = append(result, p)
+	}
+	return result, nil
+}
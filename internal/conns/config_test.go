@@ -0,0 +1,646 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package conns
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/credentials"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/session"
+)
+
+// testCredentialsFile 共享凭证文件内容
+const testCredentialsFile = `[default]
+aws_access_key_id = default-key
+aws_secret_access_key = default-secret
+
+[dev]
+aws_access_key_id = dev-key
+aws_secret_access_key = dev-secret
+`
+
+// testConfigFile 共享配置文件内容，default 的凭证会被共享凭证文件覆盖
+const testConfigFile = `[default]
+aws_access_key_id = config-key
+aws_secret_access_key = config-secret
+
+[profile ops]
+region = cn-east-1
+aws_access_key_id = ops-key
+aws_secret_access_key = ops-secret
+`
+
+// testClearAWSEnv 清除会影响凭证和 profile 解析的环境变量
+func testClearAWSEnv(t *testing.T) {
+	t.Helper()
+
+	for _, name := range []string{
+		"AWS_ACCESS_KEY_ID", "AWS_ACCESS_KEY",
+		"AWS_SECRET_ACCESS_KEY", "AWS_SECRET_KEY",
+		"AWS_SESSION_TOKEN",
+		"AWS_PROFILE", "AWS_DEFAULT_PROFILE",
+		"AWS_REGION", "AWS_DEFAULT_REGION",
+		"AWS_SHARED_CREDENTIALS_FILE", "AWS_CONFIG_FILE",
+		"AWS_SDK_LOAD_CONFIG",
+	} {
+		t.Setenv(name, "")
+	}
+	t.Setenv("HOME", t.TempDir())
+}
+
+// testCertificatePEM 生成自签名证书，返回 PEM 格式的证书和私钥
+func testCertificatePEM(t *testing.T) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("生成私钥失败: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "bingocloud-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("生成证书失败: %v", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("编码私钥失败: %v", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return string(certPEM), string(keyPEM)
+}
+
+// testWriteFile 在临时目录中写入文件，返回文件路径
+func testWriteFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	p := filepath.Join(dir, name)
+	if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+		t.Fatalf("写入文件 %s 失败: %v", p, err)
+	}
+	return p
+}
+
+func TestConfigNewSessionProfile(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     Config
+		awsProfile string
+		wantKey    string
+		wantRegion string
+		wantErr    string
+	}{
+		{
+			name:    "默认 profile",
+			config:  Config{},
+			wantKey: "default-key",
+		},
+		{
+			name:    "指定 profile",
+			config:  Config{Profile: "dev"},
+			wantKey: "dev-key",
+		},
+		{
+			name:       "AWS_PROFILE 环境变量",
+			config:     Config{},
+			awsProfile: "dev",
+			wantKey:    "dev-key",
+		},
+		{
+			name:       "配置中的 profile 优先于 AWS_PROFILE",
+			config:     Config{Profile: "default"},
+			awsProfile: "dev",
+			wantKey:    "default-key",
+		},
+		{
+			name:       "从共享配置文件读取凭证和区域",
+			config:     Config{Profile: "ops"},
+			wantKey:    "ops-key",
+			wantRegion: "cn-east-1",
+		},
+		{
+			name:    "静态密钥优先于 profile",
+			config:  Config{AccessKey: "static-key", SecretKey: "static-secret", Profile: "dev"},
+			wantKey: "static-key",
+		},
+		{
+			name:    "profile 不存在",
+			config:  Config{Profile: "missing"},
+			wantErr: `"missing"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testClearAWSEnv(t)
+			if tt.awsProfile != "" {
+				t.Setenv("AWS_PROFILE", tt.awsProfile)
+			}
+
+			tt.config.SharedConfigFiles = []string{testWriteFile(t, t.TempDir(), "config", testConfigFile)}
+			tt.config.SharedCredentialsFiles = []string{testWriteFile(t, t.TempDir(), "credentials", testCredentialsFile)}
+
+			sess, err := tt.config.newSession(&aws.Config{Endpoint: aws.String("https://bingocloud.example.com")})
+			if tt.wantErr != "" {
+				if err == nil {
+					t.Fatalf("期望返回包含 %s 的错误，实际没有错误", tt.wantErr)
+				}
+				if !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("错误信息 %q 中没有包含 %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("创建 session 失败: %v", err)
+			}
+
+			creds, err := sess.Config.Credentials.Get()
+			if err != nil {
+				t.Fatalf("解析凭证失败: %v", err)
+			}
+			if creds.AccessKeyID != tt.wantKey {
+				t.Errorf("AccessKeyID = %q，期望 %q", creds.AccessKeyID, tt.wantKey)
+			}
+			if got := aws.StringValue(sess.Config.Region); got != tt.wantRegion {
+				t.Errorf("Region = %q，期望 %q", got, tt.wantRegion)
+			}
+		})
+	}
+}
+
+func TestConfigProfileName(t *testing.T) {
+	tests := []struct {
+		name       string
+		profile    string
+		awsProfile string
+		want       string
+	}{
+		{
+			name: "默认 profile",
+			want: "default",
+		},
+		{
+			name:       "AWS_PROFILE 环境变量",
+			awsProfile: "env",
+			want:       "env",
+		},
+		{
+			name:       "配置中的 profile 优先",
+			profile:    "config",
+			awsProfile: "env",
+			want:       "config",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("AWS_PROFILE", tt.awsProfile)
+
+			c := &Config{Profile: tt.profile}
+			if got := c.profileName(); got != tt.want {
+				t.Errorf("profileName() = %q，期望 %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandPaths(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	got, err := expandPaths([]string{"~", "~/.aws/credentials", "/etc/bingocloud/config", "~other/config"})
+	if err != nil {
+		t.Fatalf("展开路径失败: %v", err)
+	}
+
+	want := []string{home, filepath.Join(home, ".aws/credentials"), "/etc/bingocloud/config", "~other/config"}
+	if len(got) != len(want) {
+		t.Fatalf("展开后的路径 %v，期望 %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("第 %d 个路径 = %q，期望 %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestConfigNewSessionAWSEnv(t *testing.T) {
+	caPEM, _ := testCertificatePEM(t)
+	dir := t.TempDir()
+	caFile := testWriteFile(t, dir, "ca.pem", caPEM)
+	missingFile := filepath.Join(dir, "missing.pem")
+
+	tests := []struct {
+		name       string
+		config     Config
+		caBundle   string
+		wantKey    string
+		wantRegion string
+		wantErr    bool
+	}{
+		{
+			name:       "使用 AWS_REGION 和 AWS_CA_BUNDLE",
+			config:     Config{AccessKey: "static-key", SecretKey: "static-secret"},
+			caBundle:   caFile,
+			wantKey:    "static-key",
+			wantRegion: "env-region",
+		},
+		{
+			name:     "AWS_CA_BUNDLE 无法读取",
+			config:   Config{AccessKey: "static-key", SecretKey: "static-secret"},
+			caBundle: missingFile,
+			wantErr:  true,
+		},
+		{
+			name:       "禁用时静态密钥忽略 AWS_REGION 和无法读取的 AWS_CA_BUNDLE",
+			config:     Config{AccessKey: "static-key", SecretKey: "static-secret", DisableAWSEnv: true},
+			caBundle:   missingFile,
+			wantKey:    "static-key",
+			wantRegion: DefaultRegion,
+		},
+		{
+			name:       "禁用时 profile 忽略 AWS_PROFILE、AWS_REGION 和 AWS_CA_BUNDLE",
+			config:     Config{DisableAWSEnv: true},
+			caBundle:   caFile,
+			wantKey:    "default-key",
+			wantRegion: DefaultRegion,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testClearAWSEnv(t)
+			t.Setenv("AWS_PROFILE", "dev")
+			t.Setenv("AWS_REGION", "env-region")
+			t.Setenv("AWS_CA_BUNDLE", tt.caBundle)
+
+			tt.config.SharedCredentialsFiles = []string{testWriteFile(t, t.TempDir(), "credentials", testCredentialsFile)}
+
+			transport := &http.Transport{}
+			sess, err := tt.config.newSession(&aws.Config{
+				Endpoint:   aws.String("https://bingocloud.example.com"),
+				HTTPClient: &http.Client{Transport: transport},
+			})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("期望创建 session 失败，实际没有错误")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("创建 session 失败: %v", err)
+			}
+
+			creds, err := sess.Config.Credentials.Get()
+			if err != nil {
+				t.Fatalf("解析凭证失败: %v", err)
+			}
+			if creds.AccessKeyID != tt.wantKey {
+				t.Errorf("AccessKeyID = %q，期望 %q", creds.AccessKeyID, tt.wantKey)
+			}
+			if got := aws.StringValue(sess.Config.Region); got != tt.wantRegion {
+				t.Errorf("Region = %q，期望 %q", got, tt.wantRegion)
+			}
+
+			// 只有启用 AWS_* 环境变量时 SDK 才会把 AWS_CA_BUNDLE 中的证书设置到 transport 上
+			gotRootCAs := transport.TLSClientConfig != nil && transport.TLSClientConfig.RootCAs != nil
+			if wantRootCAs := !tt.config.DisableAWSEnv; gotRootCAs != wantRootCAs {
+				t.Errorf("transport 是否使用 AWS_CA_BUNDLE 中的证书 = %t，期望 %t", gotRootCAs, wantRootCAs)
+			}
+		})
+	}
+}
+
+func TestConfigNewSessionCABundle(t *testing.T) {
+	certPEM, _ := testCertificatePEM(t)
+	envCertPEM, _ := testCertificatePEM(t)
+
+	testClearAWSEnv(t)
+	t.Setenv("AWS_CA_BUNDLE", testWriteFile(t, t.TempDir(), "ca.pem", envCertPEM))
+
+	c := &Config{AccessKey: "static-key", SecretKey: "static-secret", CABundle: certPEM, DisableAWSEnv: true}
+	tlsCfg, err := c.tlsConfig()
+	if err != nil {
+		t.Fatalf("构建 TLS 配置失败: %v", err)
+	}
+	transport := &http.Transport{TLSClientConfig: tlsCfg}
+
+	if _, err := c.newSession(&aws.Config{HTTPClient: &http.Client{Transport: transport}}); err != nil {
+		t.Fatalf("创建 session 失败: %v", err)
+	}
+
+	// 禁用 AWS_* 环境变量时 transport 只使用 provider 配置的 ca_bundle
+	want, err := c.tlsConfig()
+	if err != nil {
+		t.Fatalf("构建 TLS 配置失败: %v", err)
+	}
+	if !transport.TLSClientConfig.RootCAs.Equal(want.RootCAs) {
+		t.Error("transport 的 RootCAs 应为 provider 配置的 ca_bundle")
+	}
+}
+
+// testAssumeRoleResponse STS AssumeRole 接口的响应，返回临时凭证
+const testAssumeRoleResponse = `<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleResult>
+    <Credentials>
+      <AccessKeyId>temp-key</AccessKeyId>
+      <SecretAccessKey>temp-secret</SecretAccessKey>
+      <SessionToken>temp-token</SessionToken>
+      <Expiration>2099-01-01T00:00:00Z</Expiration>
+    </Credentials>
+    <AssumedRoleUser>
+      <Arn>arn:aws:sts::123456789012:assumed-role/admin/terraform</Arn>
+      <AssumedRoleId>ARO123:terraform</AssumedRoleId>
+    </AssumedRoleUser>
+  </AssumeRoleResult>
+  <ResponseMetadata><RequestId>test</RequestId></ResponseMetadata>
+</AssumeRoleResponse>`
+
+// testCallerIdentityResponse STS GetCallerIdentity 接口的响应
+const testCallerIdentityResponse = `<GetCallerIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <GetCallerIdentityResult>
+    <Arn>arn:aws:iam::123456789012:user/terraform</Arn>
+    <UserId>AIDA123</UserId>
+    <Account>123456789012</Account>
+  </GetCallerIdentityResult>
+  <ResponseMetadata><RequestId>test</RequestId></ResponseMetadata>
+</GetCallerIdentityResponse>`
+
+// testSTSErrorResponse STS 接口的错误响应
+const testSTSErrorResponse = `<ErrorResponse><Error><Type>Sender</Type><Code>AccessDenied</Code><Message>denied</Message></Error><RequestId>test</RequestId></ErrorResponse>`
+
+// testSTSRequest 测试 STS endpoint 收到的请求
+type testSTSRequest struct {
+	action        string
+	form          url.Values
+	authorization string
+	token         string
+}
+
+// testSTSServer 模拟 STS 接口的 endpoint，status 中可以指定某个接口返回的错误状态码
+type testSTSServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []testSTSRequest
+}
+
+// newTestSTSServer 启动 STS endpoint，status 中没有指定的接口正常返回
+func newTestSTSServer(t *testing.T, status map[string]int) *testSTSServer {
+	t.Helper()
+
+	s := &testSTSServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		action := r.Form.Get("Action")
+		s.mu.Lock()
+		s.requests = append(s.requests, testSTSRequest{
+			action:        action,
+			form:          r.Form,
+			authorization: r.Header.Get("Authorization"),
+			token:         r.Header.Get("X-Amz-Security-Token"),
+		})
+		s.mu.Unlock()
+
+		if code, ok := status[action]; ok {
+			w.WriteHeader(code)
+			_, _ = w.Write([]byte(testSTSErrorResponse))
+			return
+		}
+
+		switch action {
+		case "AssumeRole":
+			_, _ = w.Write([]byte(testAssumeRoleResponse))
+		case "GetCallerIdentity":
+			_, _ = w.Write([]byte(testCallerIdentityResponse))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(testSTSErrorResponse))
+		}
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// actionRequests 返回收到的指定接口的请求
+func (s *testSTSServer) actionRequests(action string) []testSTSRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []testSTSRequest
+	for _, r := range s.requests {
+		if r.action == action {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+func TestConfigAssumeRole(t *testing.T) {
+	tests := []struct {
+		name            string
+		assumeRole      AssumeRoleConfig
+		status          map[string]int
+		wantSessionName string
+		wantDuration    string
+		wantExternalID  string
+		wantErr         bool
+	}{
+		{
+			name:            "默认会话名称",
+			assumeRole:      AssumeRoleConfig{RoleARN: "arn:aws:iam::123456789012:role/admin"},
+			wantSessionName: DefaultAssumeRoleSessionName,
+			wantDuration:    "900",
+		},
+		{
+			name: "指定会话名称、有效期和外部 ID",
+			assumeRole: AssumeRoleConfig{
+				RoleARN:     "arn:aws:iam::123456789012:role/admin",
+				SessionName: "ci",
+				Duration:    time.Hour,
+				ExternalID:  "external",
+			},
+			wantSessionName: "ci",
+			wantDuration:    "3600",
+			wantExternalID:  "external",
+		},
+		{
+			name:       "扮演角色失败",
+			assumeRole: AssumeRoleConfig{RoleARN: "arn:aws:iam::123456789012:role/admin"},
+			status:     map[string]int{"AssumeRole": http.StatusForbidden},
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestSTSServer(t, tt.status)
+
+			base, err := session.NewSession(&aws.Config{
+				Endpoint:    aws.String(server.URL),
+				Region:      aws.String(DefaultRegion),
+				Credentials: credentials.NewStaticCredentials("base-key", "base-secret", ""),
+			})
+			if err != nil {
+				t.Fatalf("创建 session 失败: %v", err)
+			}
+
+			c := &Config{AssumeRole: &tt.assumeRole}
+			sess, err := c.assumeRole(context.Background(), base)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("期望扮演角色失败，实际没有错误")
+				}
+				if !strings.Contains(err.Error(), tt.assumeRole.RoleARN) {
+					t.Errorf("错误信息 %q 中没有包含角色 %s", err, tt.assumeRole.RoleARN)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("扮演角色失败: %v", err)
+			}
+
+			requests := server.actionRequests("AssumeRole")
+			if len(requests) != 1 {
+				t.Fatalf("AssumeRole 请求次数 = %d，期望 1", len(requests))
+			}
+			form := requests[0].form
+			if got := form.Get("RoleArn"); got != tt.assumeRole.RoleARN {
+				t.Errorf("RoleArn = %q，期望 %q", got, tt.assumeRole.RoleARN)
+			}
+			if got := form.Get("RoleSessionName"); got != tt.wantSessionName {
+				t.Errorf("RoleSessionName = %q，期望 %q", got, tt.wantSessionName)
+			}
+			if got := form.Get("DurationSeconds"); got != tt.wantDuration {
+				t.Errorf("DurationSeconds = %q，期望 %q", got, tt.wantDuration)
+			}
+			if got := form.Get("ExternalId"); got != tt.wantExternalID {
+				t.Errorf("ExternalId = %q，期望 %q", got, tt.wantExternalID)
+			}
+			if !strings.Contains(requests[0].authorization, "Credential=base-key/") {
+				t.Errorf("AssumeRole 请求没有使用基础凭证签名: %s", requests[0].authorization)
+			}
+
+			creds, err := sess.Config.Credentials.Get()
+			if err != nil {
+				t.Fatalf("解析临时凭证失败: %v", err)
+			}
+			if creds.AccessKeyID != "temp-key" || creds.SessionToken != "temp-token" {
+				t.Errorf("session 没有使用临时凭证: %s", creds.AccessKeyID)
+			}
+		})
+	}
+}
+
+func TestNewBingoCloudClientCallerIdentity(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     Config
+		status     map[string]int
+		wantKey    string
+		wantToken  string
+		wantAssume int
+	}{
+		{
+			name:    "静态密钥",
+			config:  Config{AccessKey: "static-key", SecretKey: "static-secret"},
+			wantKey: "static-key",
+		},
+		{
+			name:      "静态密钥和会话令牌",
+			config:    Config{AccessKey: "static-key", SecretKey: "static-secret", Token: "static-token"},
+			wantKey:   "static-key",
+			wantToken: "static-token",
+		},
+		{
+			name: "扮演角色",
+			config: Config{
+				AccessKey:  "static-key",
+				SecretKey:  "static-secret",
+				AssumeRole: &AssumeRoleConfig{RoleARN: "arn:aws:iam::123456789012:role/admin"},
+			},
+			wantKey:    "temp-key",
+			wantToken:  "temp-token",
+			wantAssume: 1,
+		},
+		{
+			name:    "查询调用者身份失败不影响创建客户端",
+			config:  Config{AccessKey: "static-key", SecretKey: "static-secret"},
+			status:  map[string]int{"GetCallerIdentity": http.StatusInternalServerError},
+			wantKey: "static-key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testClearAWSEnv(t)
+			server := newTestSTSServer(t, tt.status)
+
+			tt.config.Endpoint = server.URL
+			client, err := NewBingoCloudClient(context.Background(), &tt.config)
+			if err != nil {
+				t.Fatalf("创建客户端失败: %v", err)
+			}
+
+			creds, err := client.Config.Credentials.Get()
+			if err != nil {
+				t.Fatalf("解析凭证失败: %v", err)
+			}
+			if creds.AccessKeyID != tt.wantKey {
+				t.Errorf("AccessKeyID = %q，期望 %q", creds.AccessKeyID, tt.wantKey)
+			}
+			if creds.SessionToken != tt.wantToken {
+				t.Errorf("SessionToken = %q，期望 %q", creds.SessionToken, tt.wantToken)
+			}
+
+			if got := len(server.actionRequests("AssumeRole")); got != tt.wantAssume {
+				t.Errorf("AssumeRole 请求次数 = %d，期望 %d", got, tt.wantAssume)
+			}
+
+			// 无论使用哪种凭证都只查询一次调用者身份，失败时不重试
+			requests := server.actionRequests("GetCallerIdentity")
+			if len(requests) != 1 {
+				t.Fatalf("GetCallerIdentity 请求次数 = %d，期望 1", len(requests))
+			}
+			if !strings.Contains(requests[0].authorization, "Credential="+tt.wantKey+"/") {
+				t.Errorf("GetCallerIdentity 请求没有使用最终的凭证签名: %s", requests[0].authorization)
+			}
+			if requests[0].token != tt.wantToken {
+				t.Errorf("GetCallerIdentity 请求的会话令牌 = %q，期望 %q", requests[0].token, tt.wantToken)
+			}
+		})
+	}
+}
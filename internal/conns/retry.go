@@ -0,0 +1,278 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package conns
+
+import (
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/awserr"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/awsutil"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/client"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/request"
+)
+
+const (
+	// RetryModeStandard 按指数退避重试失败的请求
+	RetryModeStandard = "standard"
+	// RetryModeAdaptive 在 standard 的基础上，被限流后降低所有请求的发送速率
+	RetryModeAdaptive = "adaptive"
+)
+
+// DefaultMaxRetries 未配置 max_retries 时单个 API 请求的最大重试次数
+const DefaultMaxRetries = 10
+
+const (
+	retryMinDelay         = 100 * time.Millisecond
+	retryMaxDelay         = 20 * time.Second
+	retryMinThrottleDelay = 500 * time.Millisecond
+	retryMaxThrottleDelay = 60 * time.Second
+)
+
+// throttleErrorCodes BingoCloud 返回的限流错误码，SDK 内置的 AWS 限流错误码之外的部分
+var throttleErrorCodes = map[string]struct{}{
+	"RequestLimitExceeded":     {},
+	"RequestLimitExceededInfo": {},
+	"ApiCallRateExceeded":      {},
+	"TooManyRequests":          {},
+	"TooManyRequestsException": {},
+	"Throttled":                {},
+	"SlowDown":                 {},
+}
+
+// transientErrorCodes BingoCloud API 节点过载或切换时返回的临时错误码，部分节点以 4xx 状态码返回
+// 返回这些错误码时无法确定请求是否已经执行，只对可以安全重复执行的请求重试
+var transientErrorCodes = map[string]struct{}{
+	"InternalError":      {},
+	"InternalFailure":    {},
+	"ServiceUnavailable": {},
+	"Unavailable":        {},
+	"ServerBusy":         {},
+}
+
+// idempotentOperationPrefixes 只读取数据、可以安全重复执行的操作名前缀
+var idempotentOperationPrefixes = []string{"Describe", "Get", "List"}
+
+// retryer 自定义重试策略：识别 BingoCloud 的限流和临时错误码，使用带抖动的指数退避，并记录每次重试
+type retryer struct {
+	client.DefaultRetryer
+
+	// limiter 仅在 adaptive 模式下使用，为 nil 时不限制发送速率
+	limiter *adaptiveLimiter
+}
+
+var _ request.Retryer = (*retryer)(nil)
+
+// newRetryer 创建重试策略，mode 为空时使用 standard
+func newRetryer(maxRetries int, mode string) *retryer {
+	r := &retryer{
+		DefaultRetryer: client.DefaultRetryer{
+			NumMaxRetries:    maxRetries,
+			MinRetryDelay:    retryMinDelay,
+			MaxRetryDelay:    retryMaxDelay,
+			MinThrottleDelay: retryMinThrottleDelay,
+			MaxThrottleDelay: retryMaxThrottleDelay,
+		},
+	}
+	if mode == RetryModeAdaptive {
+		r.limiter = &adaptiveLimiter{}
+	}
+	return r
+}
+
+// ShouldRetry 判断请求是否需要重试
+func (r *retryer) ShouldRetry(req *request.Request) bool {
+	if r.NumMaxRetries == 0 {
+		return false
+	}
+	if req.Retryable != nil {
+		return *req.Retryable
+	}
+	if isThrottleError(req) || isTransientError(req) {
+		return true
+	}
+	return r.DefaultRetryer.ShouldRetry(req)
+}
+
+// RetryRules 计算下次重试前的等待时间并记录日志
+// 等待时间为 min(最大间隔, 最小间隔 * 2^重试次数) 的一半加上同样范围内的随机抖动，避免并发请求同时重试
+func (r *retryer) RetryRules(req *request.Request) time.Duration {
+	throttled := isThrottleError(req)
+
+	minDelay, maxDelay := r.MinRetryDelay, r.MaxRetryDelay
+	if throttled {
+		minDelay, maxDelay = r.MinThrottleDelay, r.MaxThrottleDelay
+	}
+
+	delay := maxDelay
+	if req.RetryCount < 32 {
+		if d := minDelay << uint(req.RetryCount); d > 0 && d < maxDelay {
+			delay = d
+		}
+	}
+	delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+
+	if throttled && r.limiter != nil {
+		r.limiter.throttled()
+	}
+
+	fields := map[string]interface{}{
+		"operation":   req.Operation.Name,
+		"retry_count": req.RetryCount + 1,
+		"max_retries": r.MaxRetries(),
+		"delay":       delay.String(),
+		"throttled":   throttled,
+	}
+	if req.Error != nil {
+		fields["error"] = req.Error.Error()
+	}
+	if req.HTTPResponse != nil {
+		fields["status_code"] = req.HTTPResponse.StatusCode
+	}
+	tflog.Debug(req.Context(), "API 请求失败，等待后重试", fields)
+
+	return delay
+}
+
+// isThrottleError 判断请求是否被限流
+func isThrottleError(req *request.Request) bool {
+	if req.IsErrorThrottle() {
+		return true
+	}
+	if aerr, ok := req.Error.(awserr.Error); ok {
+		_, ok := throttleErrorCodes[aerr.Code()]
+		return ok
+	}
+	return false
+}
+
+// isTransientError 判断请求是否因 BingoCloud 临时错误失败且可以安全重试
+func isTransientError(req *request.Request) bool {
+	aerr, ok := req.Error.(awserr.Error)
+	if !ok {
+		return false
+	}
+	if _, ok := transientErrorCodes[aerr.Code()]; !ok {
+		return false
+	}
+	return isIdempotentRequest(req)
+}
+
+// isIdempotentRequest 判断请求是否可以安全地重复执行：只读操作，或携带 ClientToken 由服务端去重的请求
+func isIdempotentRequest(req *request.Request) bool {
+	if req.Operation != nil {
+		for _, prefix := range idempotentOperationPrefixes {
+			if strings.HasPrefix(req.Operation.Name, prefix) {
+				return true
+			}
+		}
+	}
+
+	if req.Params == nil {
+		return false
+	}
+	values, err := awsutil.ValuesAtPath(req.Params, "ClientToken")
+	if err != nil {
+		return false
+	}
+	for _, v := range values {
+		if token, ok := v.(*string); ok && aws.StringValue(token) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// adaptiveLimiter adaptive 模式下的客户端限速
+// 被限流时加大所有请求之间的最小间隔，请求成功后逐步缩小，直到不再限速
+type adaptiveLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+const (
+	adaptiveMinInterval = 50 * time.Millisecond
+	adaptiveMaxInterval = 5 * time.Second
+)
+
+// throttled 被限流后将请求间隔加倍
+func (l *adaptiveLimiter) throttled() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.interval *= 2
+	if l.interval < adaptiveMinInterval {
+		l.interval = adaptiveMinInterval
+	}
+	if l.interval > adaptiveMaxInterval {
+		l.interval = adaptiveMaxInterval
+	}
+}
+
+// succeeded 请求成功后将请求间隔减小四分之一，低于最小间隔时取消限速
+func (l *adaptiveLimiter) succeeded() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.interval -= l.interval / 4
+	if l.interval < adaptiveMinInterval {
+		l.interval = 0
+	}
+}
+
+// reserve 预留下一个发送时间，返回需要等待的时间
+func (l *adaptiveLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.interval == 0 {
+		return 0
+	}
+
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	return wait
+}
+
+// addHandlers 在 adaptive 模式下为 session 注册限速处理器：发送前按当前间隔等待，成功后缩小间隔
+// 等待放在签名阶段，每次重试都会重新执行，等待被取消时请求不会被发送
+func (r *retryer) addHandlers(handlers *request.Handlers) {
+	if r.limiter == nil {
+		return
+	}
+
+	handlers.Sign.PushBackNamed(request.NamedHandler{
+		Name: "bingocloud.AdaptiveRateLimit",
+		Fn: func(req *request.Request) {
+			wait := r.limiter.reserve()
+			if wait == 0 {
+				return
+			}
+			tflog.Trace(req.Context(), "客户端限速，等待后发送请求", map[string]interface{}{
+				"operation": req.Operation.Name,
+				"delay":     wait.String(),
+			})
+			if err := aws.SleepWithContext(req.Context(), wait); err != nil {
+				req.Error = awserr.New(request.CanceledErrorCode, "request context canceled", err)
+			}
+		},
+	})
+	handlers.Complete.PushBackNamed(request.NamedHandler{
+		Name: "bingocloud.AdaptiveRateLimitComplete",
+		Fn: func(req *request.Request) {
+			if req.Error == nil {
+				r.limiter.succeeded()
+			}
+		},
+	})
+}
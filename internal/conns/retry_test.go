@@ -0,0 +1,304 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package conns
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/awserr"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/request"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/service/ec2"
+)
+
+// testRetryRequest 构建已收到错误响应的请求
+func testRetryRequest(code string, statusCode, retryCount int) *request.Request {
+	req := &request.Request{
+		Operation:    &request.Operation{Name: "DescribeInstances"},
+		HTTPRequest:  &http.Request{},
+		HTTPResponse: &http.Response{StatusCode: statusCode},
+		RetryCount:   retryCount,
+	}
+	if code != "" {
+		req.Error = awserr.NewRequestFailure(awserr.New(code, "test error", nil), statusCode, "request-id")
+	}
+	return req
+}
+
+// testOperationRetryRequest 构建指定操作和参数的、已收到错误响应的请求
+func testOperationRetryRequest(operation string, params interface{}, code string, statusCode int) *request.Request {
+	req := testRetryRequest(code, statusCode, 0)
+	req.Operation = &request.Operation{Name: operation}
+	req.Params = params
+	return req
+}
+
+// TestRetryerShouldRetry 测试限流错误码和可以安全重复执行的请求的临时错误码会重试，其它客户端错误不重试
+func TestRetryerShouldRetry(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxRetries int
+		req        *request.Request
+		want       bool
+	}{
+		{
+			name:       "bingocloud throttle code",
+			maxRetries: DefaultMaxRetries,
+			req:        testRetryRequest("RequestLimitExceededInfo", 400, 0),
+			want:       true,
+		},
+		{
+			name:       "bingocloud throttle code with 403",
+			maxRetries: DefaultMaxRetries,
+			req:        testRetryRequest("ApiCallRateExceeded", 403, 0),
+			want:       true,
+		},
+		{
+			name:       "sdk throttle code",
+			maxRetries: DefaultMaxRetries,
+			req:        testRetryRequest("Throttling", 400, 0),
+			want:       true,
+		},
+		{
+			name:       "transient code with 400",
+			maxRetries: DefaultMaxRetries,
+			req:        testRetryRequest("ServerBusy", 400, 0),
+			want:       true,
+		},
+		{
+			name:       "transient code with 400 on non-idempotent operation",
+			maxRetries: DefaultMaxRetries,
+			req:        testOperationRetryRequest("RunInstances", &ec2.RunInstancesInput{}, "ServerBusy", 400),
+			want:       false,
+		},
+		{
+			name:       "transient code with 400 on operation with client token",
+			maxRetries: DefaultMaxRetries,
+			req:        testOperationRetryRequest("RunInstances", &ec2.RunInstancesInput{ClientToken: aws.String("token")}, "ServerBusy", 400),
+			want:       true,
+		},
+		{
+			name:       "transient code with 400 on get operation",
+			maxRetries: DefaultMaxRetries,
+			req:        testOperationRetryRequest("GetConsoleOutput", &ec2.GetConsoleOutputInput{}, "InternalError", 400),
+			want:       true,
+		},
+		{
+			name:       "throttle code on non-idempotent operation",
+			maxRetries: DefaultMaxRetries,
+			req:        testOperationRetryRequest("AllocateAddress", &ec2.AllocateAddressInput{}, "RequestLimitExceeded", 400),
+			want:       true,
+		},
+		{
+			name:       "service unavailable status",
+			maxRetries: DefaultMaxRetries,
+			req:        testRetryRequest("UnknownError", 503, 0),
+			want:       true,
+		},
+		{
+			name:       "invalid parameter",
+			maxRetries: DefaultMaxRetries,
+			req:        testRetryRequest("InvalidParameterValue", 400, 0),
+			want:       false,
+		},
+		{
+			name:       "auth failure",
+			maxRetries: DefaultMaxRetries,
+			req:        testRetryRequest("AuthFailure", 401, 0),
+			want:       false,
+		},
+		{
+			name:       "max retries zero",
+			maxRetries: 0,
+			req:        testRetryRequest("RequestLimitExceeded", 400, 0),
+			want:       false,
+		},
+		{
+			name:       "retryable set by handler",
+			maxRetries: DefaultMaxRetries,
+			req: func() *request.Request {
+				req := testRetryRequest("RequestLimitExceeded", 400, 0)
+				req.Retryable = aws.Bool(false)
+				return req
+			}(),
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRetryer(tt.maxRetries, RetryModeStandard)
+			if got := r.ShouldRetry(tt.req); got != tt.want {
+				t.Errorf("ShouldRetry 应为 %t，得到 %t", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestRetryerErrorCodes 测试所有 BingoCloud 限流和临时错误码都会重试，只有限流错误码使用限流退避
+func TestRetryerErrorCodes(t *testing.T) {
+	r := newRetryer(DefaultMaxRetries, RetryModeStandard)
+
+	for code := range throttleErrorCodes {
+		req := testRetryRequest(code, 400, 0)
+		if !r.ShouldRetry(req) || !isThrottleError(req) {
+			t.Errorf("限流错误码 %s 应重试并按限流处理", code)
+		}
+	}
+	for code := range transientErrorCodes {
+		req := testRetryRequest(code, 400, 0)
+		if !r.ShouldRetry(req) || isThrottleError(req) {
+			t.Errorf("临时错误码 %s 应重试且不按限流处理", code)
+		}
+	}
+}
+
+// TestRetryerRetryRules 测试等待时间在带抖动的指数退避范围内，限流错误使用更长的间隔
+func TestRetryerRetryRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      *request.Request
+		min, max time.Duration
+	}{
+		{
+			name: "first retry",
+			req:  testRetryRequest("InternalError", 500, 0),
+			min:  retryMinDelay / 2,
+			max:  retryMinDelay,
+		},
+		{
+			name: "exponential backoff",
+			req:  testRetryRequest("InternalError", 500, 3),
+			min:  retryMinDelay * 8 / 2,
+			max:  retryMinDelay * 8,
+		},
+		{
+			name: "capped at max delay",
+			req:  testRetryRequest("InternalError", 500, 40),
+			min:  retryMaxDelay / 2,
+			max:  retryMaxDelay,
+		},
+		{
+			name: "throttled first retry",
+			req:  testRetryRequest("RequestLimitExceeded", 400, 0),
+			min:  retryMinThrottleDelay / 2,
+			max:  retryMinThrottleDelay,
+		},
+		{
+			name: "throttled capped at max delay",
+			req:  testRetryRequest("RequestLimitExceeded", 400, 10),
+			min:  retryMaxThrottleDelay / 2,
+			max:  retryMaxThrottleDelay,
+		},
+	}
+
+	r := newRetryer(DefaultMaxRetries, RetryModeStandard)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 抖动是随机的，多次计算确认都在范围内
+			for i := 0; i < 100; i++ {
+				if got := r.RetryRules(tt.req); got < tt.min || got > tt.max {
+					t.Fatalf("等待时间应在 [%s, %s] 内，得到 %s", tt.min, tt.max, got)
+				}
+			}
+		})
+	}
+}
+
+// TestAdaptiveLimiter 测试 adaptive 模式下被限流后加大请求间隔，请求成功后逐步取消限速
+func TestAdaptiveLimiter(t *testing.T) {
+	if newRetryer(DefaultMaxRetries, RetryModeStandard).limiter != nil {
+		t.Fatal("standard 模式不应限速")
+	}
+
+	r := newRetryer(DefaultMaxRetries, RetryModeAdaptive)
+	if r.limiter == nil {
+		t.Fatal("adaptive 模式应创建限速器")
+	}
+
+	steps := []struct {
+		name string
+		fn   func()
+		want time.Duration
+	}{
+		{"not throttled", func() { r.RetryRules(testRetryRequest("InternalError", 500, 0)) }, 0},
+		{"first throttle", func() { r.RetryRules(testRetryRequest("RequestLimitExceeded", 400, 0)) }, adaptiveMinInterval},
+		{"second throttle", func() { r.RetryRules(testRetryRequest("RequestLimitExceeded", 400, 1)) }, 2 * adaptiveMinInterval},
+		{"first success", r.limiter.succeeded, 75 * time.Millisecond},
+		{"second success", r.limiter.succeeded, 56250 * time.Microsecond},
+		{"below min interval", r.limiter.succeeded, 0},
+	}
+	for _, step := range steps {
+		step.fn()
+		if r.limiter.interval != step.want {
+			t.Fatalf("%s: 请求间隔应为 %s，得到 %s", step.name, step.want, r.limiter.interval)
+		}
+	}
+
+	for i := 0; i < 20; i++ {
+		r.limiter.throttled()
+	}
+	if r.limiter.interval != adaptiveMaxInterval {
+		t.Errorf("请求间隔应不超过 %s，得到 %s", adaptiveMaxInterval, r.limiter.interval)
+	}
+}
+
+// TestAdaptiveLimiterReserve 测试限速时连续的请求按间隔依次发送
+func TestAdaptiveLimiterReserve(t *testing.T) {
+	l := &adaptiveLimiter{}
+	if wait := l.reserve(); wait != 0 {
+		t.Fatalf("未限速时不应等待，得到 %s", wait)
+	}
+
+	l.interval = time.Second
+	if wait := l.reserve(); wait != 0 {
+		t.Fatalf("第一个请求不应等待，得到 %s", wait)
+	}
+	if wait := l.reserve(); wait <= 0 || wait > time.Second {
+		t.Fatalf("第二个请求应等待不超过 %s，得到 %s", time.Second, wait)
+	}
+	if wait := l.reserve(); wait <= time.Second || wait > 2*time.Second {
+		t.Fatalf("第三个请求应等待 (%s, %s]，得到 %s", time.Second, 2*time.Second, wait)
+	}
+}
+
+// TestRetryerAddHandlers 测试只有 adaptive 模式注册限速处理器，等待被取消时请求返回取消错误
+func TestRetryerAddHandlers(t *testing.T) {
+	var standard request.Handlers
+	newRetryer(DefaultMaxRetries, RetryModeStandard).addHandlers(&standard)
+	if standard.Sign.Len() != 0 || standard.Complete.Len() != 0 {
+		t.Fatal("standard 模式不应注册限速处理器")
+	}
+
+	r := newRetryer(DefaultMaxRetries, RetryModeAdaptive)
+	var adaptive request.Handlers
+	r.addHandlers(&adaptive)
+	if adaptive.Sign.Len() != 1 || adaptive.Complete.Len() != 1 {
+		t.Fatalf("adaptive 模式应注册签名和完成处理器，得到 %d 和 %d", adaptive.Sign.Len(), adaptive.Complete.Len())
+	}
+
+	r.limiter.interval = time.Minute
+	r.limiter.reserve()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := testRetryRequest("", 200, 0)
+	req.SetContext(ctx)
+	adaptive.Sign.Run(req)
+
+	var aerr awserr.Error
+	if !errors.As(req.Error, &aerr) || aerr.Code() != request.CanceledErrorCode {
+		t.Fatalf("等待被取消时应返回 %s 错误，得到 %v", request.CanceledErrorCode, req.Error)
+	}
+
+	// 失败的请求不会缩小请求间隔
+	adaptive.Complete.Run(req)
+	if r.limiter.interval != time.Minute {
+		t.Errorf("失败的请求不应改变请求间隔，得到 %s", r.limiter.interval)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package conns
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// TLSConfigError 表示 TLS 相关配置无效，Attribute 为出错的 provider 属性名
+type TLSConfigError struct {
+	Attribute string
+	Err       error
+}
+
+func (e *TLSConfigError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Attribute, e.Err)
+}
+
+func (e *TLSConfigError) Unwrap() error {
+	return e.Err
+}
+
+// tlsConfig 根据配置构建 TLS 配置，未配置任何 TLS 选项时返回 nil 使用默认配置
+func (c *Config) tlsConfig() (*tls.Config, error) {
+	if !c.InsecureSkipTLS && c.CABundle == "" && c.ClientCertificate == "" && c.ClientKey == "" {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{
+		InsecureSkipVerify: c.InsecureSkipTLS,
+	}
+
+	// 自定义 CA：在系统根证书的基础上追加，内部 CA 签发的证书和公共证书都能通过验证
+	if c.CABundle != "" {
+		data, err := readPEM(c.CABundle)
+		if err != nil {
+			return nil, &TLSConfigError{Attribute: "ca_bundle", Err: err}
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, &TLSConfigError{Attribute: "ca_bundle", Err: fmt.Errorf("没有找到有效的 PEM 格式证书")}
+		}
+		cfg.RootCAs = pool
+	}
+
+	// 双向 TLS：客户端证书和私钥必须同时配置
+	if c.ClientCertificate != "" || c.ClientKey != "" {
+		if c.ClientCertificate == "" {
+			return nil, &TLSConfigError{Attribute: "client_certificate", Err: fmt.Errorf("配置了 client_key 时必须同时配置 client_certificate")}
+		}
+		if c.ClientKey == "" {
+			return nil, &TLSConfigError{Attribute: "client_key", Err: fmt.Errorf("配置了 client_certificate 时必须同时配置 client_key")}
+		}
+
+		certPEM, err := readPEM(c.ClientCertificate)
+		if err != nil {
+			return nil, &TLSConfigError{Attribute: "client_certificate", Err: err}
+		}
+		keyPEM, err := readPEM(c.ClientKey)
+		if err != nil {
+			return nil, &TLSConfigError{Attribute: "client_key", Err: err}
+		}
+
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, &TLSConfigError{Attribute: "client_certificate", Err: fmt.Errorf("客户端证书与私钥无效或不匹配: %w", err)}
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+// readPEM 读取 PEM 内容：值本身是 PEM 时直接使用，否则视为文件路径读取
+func readPEM(value string) ([]byte, error) {
+	if strings.Contains(value, "-----BEGIN") {
+		return []byte(value), nil
+	}
+
+	paths, err := expandPaths([]string{value})
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(paths[0])
+	if err != nil {
+		return nil, fmt.Errorf("读取文件 %s 失败: %w", value, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,191 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package conns
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// TestConfigTLSConfig 测试 TLS 配置的构建，出错时错误归属到对应的 provider 属性
+func TestConfigTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	certPEM, keyPEM := testCertificatePEM(t)
+	_, otherKeyPEM := testCertificatePEM(t)
+
+	certFile := testWriteFile(t, dir, "cert.pem", certPEM)
+	keyFile := testWriteFile(t, dir, "key.pem", keyPEM)
+	invalidFile := testWriteFile(t, dir, "invalid.pem", "not a certificate")
+	missingFile := filepath.Join(dir, "missing.pem")
+
+	tests := []struct {
+		name          string
+		config        Config
+		wantNil       bool
+		wantAttribute string
+		wantInsecure  bool
+		wantRootCAs   bool
+		wantCerts     int
+	}{
+		{
+			name:    "no tls options",
+			config:  Config{},
+			wantNil: true,
+		},
+		{
+			name:         "insecure skip verify",
+			config:       Config{InsecureSkipTLS: true},
+			wantInsecure: true,
+		},
+		{
+			name:        "ca bundle content",
+			config:      Config{CABundle: certPEM},
+			wantRootCAs: true,
+		},
+		{
+			name:        "ca bundle file",
+			config:      Config{CABundle: certFile},
+			wantRootCAs: true,
+		},
+		{
+			name:          "ca bundle missing file",
+			config:        Config{CABundle: missingFile},
+			wantAttribute: "ca_bundle",
+		},
+		{
+			name:          "ca bundle invalid content",
+			config:        Config{CABundle: invalidFile},
+			wantAttribute: "ca_bundle",
+		},
+		{
+			name:      "client certificate content",
+			config:    Config{ClientCertificate: certPEM, ClientKey: keyPEM},
+			wantCerts: 1,
+		},
+		{
+			name:      "client certificate files",
+			config:    Config{ClientCertificate: certFile, ClientKey: keyFile},
+			wantCerts: 1,
+		},
+		{
+			name:          "client key without certificate",
+			config:        Config{ClientKey: keyPEM},
+			wantAttribute: "client_certificate",
+		},
+		{
+			name:          "client certificate without key",
+			config:        Config{ClientCertificate: certPEM},
+			wantAttribute: "client_key",
+		},
+		{
+			name:          "client certificate missing file",
+			config:        Config{ClientCertificate: missingFile, ClientKey: keyPEM},
+			wantAttribute: "client_certificate",
+		},
+		{
+			name:          "client key missing file",
+			config:        Config{ClientCertificate: certPEM, ClientKey: missingFile},
+			wantAttribute: "client_key",
+		},
+		{
+			name:          "mismatched key",
+			config:        Config{ClientCertificate: certPEM, ClientKey: otherKeyPEM},
+			wantAttribute: "client_certificate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := tt.config.tlsConfig()
+			if tt.wantAttribute != "" {
+				var tlsErr *TLSConfigError
+				if !errors.As(err, &tlsErr) {
+					t.Fatalf("应返回 TLSConfigError，得到 %v", err)
+				}
+				if tlsErr.Attribute != tt.wantAttribute {
+					t.Errorf("错误应归属到 %s，得到 %s: %v", tt.wantAttribute, tlsErr.Attribute, tlsErr.Err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("构建 TLS 配置失败: %v", err)
+			}
+
+			if tt.wantNil {
+				if cfg != nil {
+					t.Errorf("未配置 TLS 选项时应返回 nil，得到 %+v", cfg)
+				}
+				return
+			}
+			if cfg == nil {
+				t.Fatal("TLS 配置不应为 nil")
+			}
+			if cfg.InsecureSkipVerify != tt.wantInsecure {
+				t.Errorf("InsecureSkipVerify 应为 %t，得到 %t", tt.wantInsecure, cfg.InsecureSkipVerify)
+			}
+			if (cfg.RootCAs != nil) != tt.wantRootCAs {
+				t.Errorf("RootCAs 是否设置应为 %t", tt.wantRootCAs)
+			}
+			if len(cfg.Certificates) != tt.wantCerts {
+				t.Errorf("客户端证书数量应为 %d，得到 %d", tt.wantCerts, len(cfg.Certificates))
+			}
+		})
+	}
+}
+
+// TestReadPEM 测试 PEM 内容直接使用，其它值按文件路径读取并展开 ~
+func TestReadPEM(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	certPEM, _ := testCertificatePEM(t)
+	certFile := testWriteFile(t, home, "ca.pem", certPEM)
+
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "pem content",
+			value: certPEM,
+			want:  certPEM,
+		},
+		{
+			name:  "absolute path",
+			value: certFile,
+			want:  certPEM,
+		},
+		{
+			name:  "home directory path",
+			value: "~/ca.pem",
+			want:  certPEM,
+		},
+		{
+			name:    "missing file",
+			value:   filepath.Join(home, "missing.pem"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := readPEM(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("应返回错误")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("读取 PEM 失败: %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("PEM 内容不一致，得到 %q", data)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package conns
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// defaultKeepAlive 自定义连接超时时 TCP keep-alive 的间隔，与 http.DefaultTransport 一致
+const defaultKeepAlive = 30 * time.Second
+
+// httpClient 构建 SDK 使用的 HTTP 客户端
+// 以 http.DefaultTransport 的副本为基础，保留环境变量代理、连接池和超时的默认值，再叠加 provider 的配置
+func (c *Config) httpClient() (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	// 自定义 CA、客户端证书或跳过 TLS 验证（私有云环境常用）
+	tlsCfg, err := c.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg != nil {
+		transport.TLSClientConfig = tlsCfg
+	}
+
+	// 代理：未配置的部分仍然使用 HTTP_PROXY、HTTPS_PROXY 和 NO_PROXY 环境变量
+	if c.HTTPProxy != "" || c.NoProxy != "" {
+		proxyCfg := httpproxy.FromEnvironment()
+		if c.HTTPProxy != "" {
+			proxyCfg.HTTPProxy = c.HTTPProxy
+			proxyCfg.HTTPSProxy = c.HTTPProxy
+		}
+		if c.NoProxy != "" {
+			proxyCfg.NoProxy = c.NoProxy
+		}
+		proxyFunc := proxyCfg.ProxyFunc()
+		transport.Proxy = func(r *http.Request) (*url.URL, error) {
+			return proxyFunc(r.URL)
+		}
+	}
+
+	if c.ConnectTimeout > 0 {
+		dialer := &net.Dialer{
+			Timeout:   c.ConnectTimeout,
+			KeepAlive: defaultKeepAlive,
+		}
+		transport.DialContext = dialer.DialContext
+		transport.TLSHandshakeTimeout = c.ConnectTimeout
+	}
+
+	// 所有请求都发往同一个 endpoint，每个主机的空闲连接数与总数保持一致
+	if c.MaxIdleConns > 0 {
+		transport.MaxIdleConns = c.MaxIdleConns
+		transport.MaxIdleConnsPerHost = c.MaxIdleConns
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   c.RequestTimeout,
+	}, nil
+}
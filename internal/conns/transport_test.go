@@ -0,0 +1,190 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package conns
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+// testClearProxyEnv 清除代理相关的环境变量
+func testClearProxyEnv(t *testing.T) {
+	t.Helper()
+
+	for _, name := range []string{"HTTP_PROXY", "http_proxy", "HTTPS_PROXY", "https_proxy", "NO_PROXY", "no_proxy", "REQUEST_METHOD"} {
+		t.Setenv(name, "")
+	}
+}
+
+func TestConfigHTTPClientProxy(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		env    map[string]string
+		// 请求地址对应的代理地址，空字符串表示直连
+		want map[string]string
+	}{
+		{
+			name:   "http_proxy 同时用于 HTTP 和 HTTPS",
+			config: Config{HTTPProxy: "http://proxy.example.com:3128"},
+			want: map[string]string{
+				"http://api.example.com":  "http://proxy.example.com:3128",
+				"https://api.example.com": "http://proxy.example.com:3128",
+			},
+		},
+		{
+			name:   "no_proxy 中的主机直连",
+			config: Config{HTTPProxy: "http://proxy.example.com:3128", NoProxy: "internal.example.com"},
+			want: map[string]string{
+				"https://api.example.com":      "http://proxy.example.com:3128",
+				"https://internal.example.com": "",
+			},
+		},
+		{
+			name:   "只配置 no_proxy 时使用环境变量中的代理",
+			config: Config{NoProxy: "internal.example.com"},
+			env:    map[string]string{"HTTPS_PROXY": "http://env-proxy.example.com:8080"},
+			want: map[string]string{
+				"https://api.example.com":      "http://env-proxy.example.com:8080",
+				"https://internal.example.com": "",
+			},
+		},
+		{
+			name:   "配置的 http_proxy 优先于环境变量",
+			config: Config{HTTPProxy: "http://proxy.example.com:3128"},
+			env: map[string]string{
+				"HTTPS_PROXY": "http://env-proxy.example.com:8080",
+				"NO_PROXY":    "internal.example.com",
+			},
+			want: map[string]string{
+				"https://api.example.com":      "http://proxy.example.com:3128",
+				"https://internal.example.com": "",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testClearProxyEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			client, err := tt.config.httpClient()
+			if err != nil {
+				t.Fatalf("构建 HTTP 客户端失败: %v", err)
+			}
+			transport := client.Transport.(*http.Transport)
+
+			for target, want := range tt.want {
+				req, err := http.NewRequest(http.MethodGet, target, nil)
+				if err != nil {
+					t.Fatalf("构建请求失败: %v", err)
+				}
+				proxyURL, err := transport.Proxy(req)
+				if err != nil {
+					t.Fatalf("解析 %s 的代理失败: %v", target, err)
+				}
+
+				got := ""
+				if proxyURL != nil {
+					got = proxyURL.String()
+				}
+				if got != want {
+					t.Errorf("%s 的代理 = %q，期望 %q", target, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestConfigHTTPClientTransport(t *testing.T) {
+	defaultTransport := http.DefaultTransport.(*http.Transport)
+
+	tests := []struct {
+		name                    string
+		config                  Config
+		wantTimeout             time.Duration
+		wantTLSHandshakeTimeout time.Duration
+		wantMaxIdleConns        int
+		wantMaxIdleConnsPerHost int
+		wantInsecure            bool
+	}{
+		{
+			name:                    "默认值",
+			config:                  Config{},
+			wantTLSHandshakeTimeout: defaultTransport.TLSHandshakeTimeout,
+			wantMaxIdleConns:        defaultTransport.MaxIdleConns,
+			wantMaxIdleConnsPerHost: defaultTransport.MaxIdleConnsPerHost,
+		},
+		{
+			name:                    "连接超时",
+			config:                  Config{ConnectTimeout: 5 * time.Second},
+			wantTLSHandshakeTimeout: 5 * time.Second,
+			wantMaxIdleConns:        defaultTransport.MaxIdleConns,
+			wantMaxIdleConnsPerHost: defaultTransport.MaxIdleConnsPerHost,
+		},
+		{
+			name:                    "请求超时",
+			config:                  Config{RequestTimeout: time.Minute},
+			wantTimeout:             time.Minute,
+			wantTLSHandshakeTimeout: defaultTransport.TLSHandshakeTimeout,
+			wantMaxIdleConns:        defaultTransport.MaxIdleConns,
+			wantMaxIdleConnsPerHost: defaultTransport.MaxIdleConnsPerHost,
+		},
+		{
+			name:                    "空闲连接数",
+			config:                  Config{MaxIdleConns: 20},
+			wantTLSHandshakeTimeout: defaultTransport.TLSHandshakeTimeout,
+			wantMaxIdleConns:        20,
+			wantMaxIdleConnsPerHost: 20,
+		},
+		{
+			name:                    "跳过 TLS 验证",
+			config:                  Config{InsecureSkipTLS: true},
+			wantTLSHandshakeTimeout: defaultTransport.TLSHandshakeTimeout,
+			wantMaxIdleConns:        defaultTransport.MaxIdleConns,
+			wantMaxIdleConnsPerHost: defaultTransport.MaxIdleConnsPerHost,
+			wantInsecure:            true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := tt.config.httpClient()
+			if err != nil {
+				t.Fatalf("构建 HTTP 客户端失败: %v", err)
+			}
+			transport := client.Transport.(*http.Transport)
+
+			if transport == defaultTransport {
+				t.Fatal("HTTP 客户端不应直接使用 http.DefaultTransport")
+			}
+			if client.Timeout != tt.wantTimeout {
+				t.Errorf("Timeout = %s，期望 %s", client.Timeout, tt.wantTimeout)
+			}
+			if transport.TLSHandshakeTimeout != tt.wantTLSHandshakeTimeout {
+				t.Errorf("TLSHandshakeTimeout = %s，期望 %s", transport.TLSHandshakeTimeout, tt.wantTLSHandshakeTimeout)
+			}
+			if transport.MaxIdleConns != tt.wantMaxIdleConns {
+				t.Errorf("MaxIdleConns = %d，期望 %d", transport.MaxIdleConns, tt.wantMaxIdleConns)
+			}
+			if transport.MaxIdleConnsPerHost != tt.wantMaxIdleConnsPerHost {
+				t.Errorf("MaxIdleConnsPerHost = %d，期望 %d", transport.MaxIdleConnsPerHost, tt.wantMaxIdleConnsPerHost)
+			}
+			if got := transport.TLSClientConfig != nil && transport.TLSClientConfig.InsecureSkipVerify; got != tt.wantInsecure {
+				t.Errorf("InsecureSkipVerify = %t，期望 %t", got, tt.wantInsecure)
+			}
+			if transport.Proxy == nil {
+				t.Error("未配置代理时应使用环境变量中的代理")
+			}
+		})
+	}
+
+	// 构建客户端不能修改 http.DefaultTransport
+	if defaultTransport.MaxIdleConnsPerHost == 20 || defaultTransport.TLSHandshakeTimeout == 5*time.Second {
+		t.Error("http.DefaultTransport 被修改")
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package conns
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/awserr"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/request"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/service/ec2"
+)
+
+// ValidationErrorKind 凭证和 endpoint 校验失败的类型
+type ValidationErrorKind string
+
+const (
+	// ValidationErrorEndpoint endpoint 无法连接或返回的不是 BingoCloud API 响应
+	ValidationErrorEndpoint ValidationErrorKind = "endpoint"
+	// ValidationErrorDNS endpoint 主机名无法解析
+	ValidationErrorDNS ValidationErrorKind = "dns"
+	// ValidationErrorTLS TLS 握手或证书验证失败
+	ValidationErrorTLS ValidationErrorKind = "tls"
+	// ValidationErrorAuth 凭证无效或签名不匹配
+	ValidationErrorAuth ValidationErrorKind = "auth"
+	// ValidationErrorUnknown 无法归类的错误
+	ValidationErrorUnknown ValidationErrorKind = "unknown"
+)
+
+// validationMaxRetries 校验请求的最大重试次数，DNS、TLS 等配置错误不会因为重试而恢复，避免长时间等待
+const validationMaxRetries = 2
+
+// authErrorCodes 表示凭证无效的错误码
+var authErrorCodes = map[string]struct{}{
+	"AuthFailure":                 {},
+	"InvalidAccessKeyId":          {},
+	"InvalidClientTokenId":        {},
+	"SignatureDoesNotMatch":       {},
+	"IncompleteSignature":         {},
+	"UnrecognizedClientException": {},
+	"ExpiredToken":                {},
+	"RequestExpired":              {},
+	"MissingAuthenticationToken":  {},
+}
+
+// permissionErrorCodes 表示凭证有效但没有调用校验接口权限的错误码，这种情况视为校验通过
+var permissionErrorCodes = map[string]struct{}{
+	"UnauthorizedOperation": {},
+	"AccessDenied":          {},
+	"AccessDeniedException": {},
+}
+
+// ValidationError 凭证和 endpoint 校验失败，Kind 为失败类型
+type ValidationError struct {
+	Kind ValidationErrorKind
+	Err  error
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Kind, e.Err)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateCredentials 调用 DescribeAvailabilityZones 校验 endpoint 可用且凭证有效
+func (c *BingoCloudClient) ValidateCredentials(ctx context.Context) error {
+	_, err := c.EC2Client().DescribeAvailabilityZonesWithContext(ctx, &ec2.DescribeAvailabilityZonesInput{}, func(r *request.Request) {
+		r.Retryer = newRetryer(validationMaxRetries, RetryModeStandard)
+	})
+	if err == nil {
+		return nil
+	}
+
+	var aerr awserr.Error
+	if errors.As(err, &aerr) {
+		if _, ok := permissionErrorCodes[aerr.Code()]; ok {
+			tflog.Debug(ctx, "凭证没有 DescribeAvailabilityZones 权限，跳过凭证校验", map[string]interface{}{
+				"error": err.Error(),
+			})
+			return nil
+		}
+	}
+
+	return &ValidationError{Kind: classifyValidationError(err), Err: err}
+}
+
+// classifyValidationError 判断校验失败的类型
+func classifyValidationError(err error) ValidationErrorKind {
+	if aerr, ok := err.(awserr.Error); ok {
+		if _, ok := authErrorCodes[aerr.Code()]; ok {
+			return ValidationErrorAuth
+		}
+	}
+
+	// SDK 的错误不支持 errors.Unwrap，沿 OrigErr 找到底层的网络错误
+	for e := err; e != nil; e = origErr(e) {
+		var dnsErr *net.DNSError
+		if errors.As(e, &dnsErr) {
+			return ValidationErrorDNS
+		}
+
+		var (
+			unknownAuthorityErr *x509.UnknownAuthorityError
+			hostnameErr         x509.HostnameError
+			certInvalidErr      x509.CertificateInvalidError
+			verificationErr     *tls.CertificateVerificationError
+			recordHeaderErr     tls.RecordHeaderError
+			alertErr            tls.AlertError
+		)
+		if errors.As(e, &unknownAuthorityErr) || errors.As(e, &hostnameErr) || errors.As(e, &certInvalidErr) ||
+			errors.As(e, &verificationErr) || errors.As(e, &recordHeaderErr) || errors.As(e, &alertErr) {
+			return ValidationErrorTLS
+		}
+
+		var netErr net.Error
+		if errors.As(e, &netErr) {
+			return ValidationErrorEndpoint
+		}
+	}
+
+	// 请求已发送但响应无法解析，或者 endpoint 返回了 404 等非 API 响应
+	if aerr, ok := err.(awserr.Error); ok {
+		switch aerr.Code() {
+		case request.ErrCodeSerialization, request.ErrCodeRequestError, request.ErrCodeResponseTimeout, "UnknownError":
+			return ValidationErrorEndpoint
+		}
+	}
+	var reqErr awserr.RequestFailure
+	if errors.As(err, &reqErr) && reqErr.StatusCode() == 404 {
+		return ValidationErrorEndpoint
+	}
+
+	return ValidationErrorUnknown
+}
+
+// origErr 返回 SDK 错误包装的底层错误
+func origErr(err error) error {
+	if aerr, ok := err.(awserr.Error); ok {
+		return aerr.OrigErr()
+	}
+	return errors.Unwrap(err)
+}
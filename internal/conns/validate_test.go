@@ -0,0 +1,102 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package conns
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/url"
+	"testing"
+
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/awserr"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/request"
+)
+
+// testSendError 构建 SDK 发送请求失败时返回的错误
+func testSendError(err error) error {
+	return awserr.New(request.ErrCodeRequestError, "send request failed", &url.Error{
+		Op:  "Post",
+		URL: "https://bingocloud.example.com/main/",
+		Err: err,
+	})
+}
+
+// TestClassifyValidationError 测试按 SDK 错误码和底层网络错误判断校验失败的类型
+func TestClassifyValidationError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ValidationErrorKind
+	}{
+		{
+			name: "auth failure",
+			err:  awserr.NewRequestFailure(awserr.New("AuthFailure", "invalid credentials", nil), 401, "request-id"),
+			want: ValidationErrorAuth,
+		},
+		{
+			name: "signature does not match",
+			err:  awserr.NewRequestFailure(awserr.New("SignatureDoesNotMatch", "signature mismatch", nil), 403, "request-id"),
+			want: ValidationErrorAuth,
+		},
+		{
+			name: "dns error",
+			err: testSendError(&net.OpError{
+				Op:  "dial",
+				Net: "tcp",
+				Err: &net.DNSError{Err: "no such host", Name: "bingocloud.example.com", IsNotFound: true},
+			}),
+			want: ValidationErrorDNS,
+		},
+		{
+			name: "unknown certificate authority",
+			err:  testSendError(&tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}}),
+			want: ValidationErrorTLS,
+		},
+		{
+			name: "hostname mismatch",
+			err:  testSendError(x509.HostnameError{Certificate: &x509.Certificate{}, Host: "bingocloud.example.com"}),
+			want: ValidationErrorTLS,
+		},
+		{
+			name: "plain http endpoint",
+			err:  testSendError(tls.RecordHeaderError{Msg: "first record does not look like a TLS handshake"}),
+			want: ValidationErrorTLS,
+		},
+		{
+			name: "connection refused",
+			err:  testSendError(&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}),
+			want: ValidationErrorEndpoint,
+		},
+		{
+			name: "serialization error",
+			err:  awserr.New(request.ErrCodeSerialization, "failed to decode response", nil),
+			want: ValidationErrorEndpoint,
+		},
+		{
+			name: "not found",
+			err:  awserr.NewRequestFailure(awserr.New("NotFound", "not found", nil), 404, "request-id"),
+			want: ValidationErrorEndpoint,
+		},
+		{
+			name: "other api error",
+			err:  awserr.NewRequestFailure(awserr.New("InvalidParameterValue", "invalid parameter", nil), 400, "request-id"),
+			want: ValidationErrorUnknown,
+		},
+		{
+			name: "non sdk error",
+			err:  errors.New("unexpected error"),
+			want: ValidationErrorUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyValidationError(tt.err); got != tt.want {
+				t.Errorf("错误类型应为 %s，得到 %s", tt.want, got)
+			}
+		})
+	}
+}
@@ -5,18 +5,28 @@ package provider
 
 import (
 	"context"
+	"errors"
+	"net/url"
 	"os"
+	"strconv"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/action"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
 	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/mulei1288/terraform-provider-bingocloud/internal/conns"
 	"github.com/mulei1288/terraform-provider-bingocloud/internal/service/ec2"
+	tftags "github.com/mulei1288/terraform-provider-bingocloud/internal/tags"
 )
 
 // Ensure BingoCloudProvider satisfies various provider interfaces.
@@ -38,8 +48,55 @@ type BingoCloudProviderModel struct {
 	Endpoint        types.String `tfsdk:"endpoint"`
 	AccessKey       types.String `tfsdk:"access_key"`
 	SecretKey       types.String `tfsdk:"secret_key"`
+	Token           types.String `tfsdk:"token"`
 	Region          types.String `tfsdk:"region"`
 	InsecureSkipTLS types.Bool   `tfsdk:"insecure_skip_tls"`
+
+	CABundle          types.String `tfsdk:"ca_bundle"`
+	ClientCertificate types.String `tfsdk:"client_certificate"`
+	ClientKey         types.String `tfsdk:"client_key"`
+
+	Profile                types.String `tfsdk:"profile"`
+	SharedCredentialsFiles types.List   `tfsdk:"shared_credentials_files"`
+	SharedConfigFiles      types.List   `tfsdk:"shared_config_files"`
+
+	DisableAWSEnvFallback types.Bool `tfsdk:"disable_aws_env_fallback"`
+
+	HTTPProxy      types.String `tfsdk:"http_proxy"`
+	NoProxy        types.String `tfsdk:"no_proxy"`
+	ConnectTimeout types.String `tfsdk:"connect_timeout"`
+	RequestTimeout types.String `tfsdk:"request_timeout"`
+	MaxIdleConns   types.Int64  `tfsdk:"max_idle_conns"`
+
+	MaxRetries types.Int64  `tfsdk:"max_retries"`
+	RetryMode  types.String `tfsdk:"retry_mode"`
+
+	SkipCredentialsValidation types.Bool `tfsdk:"skip_credentials_validation"`
+
+	TerminateOnCreateFailure types.Bool `tfsdk:"terminate_on_create_failure"`
+
+	AssumeRole  *AssumeRoleModel  `tfsdk:"assume_role"`
+	DefaultTags *DefaultTagsModel `tfsdk:"default_tags"`
+	IgnoreTags  *IgnoreTagsModel  `tfsdk:"ignore_tags"`
+}
+
+// AssumeRoleModel describes the assume_role block.
+type AssumeRoleModel struct {
+	RoleARN     types.String `tfsdk:"role_arn"`
+	SessionName types.String `tfsdk:"session_name"`
+	Duration    types.String `tfsdk:"duration"`
+	ExternalID  types.String `tfsdk:"external_id"`
+}
+
+// DefaultTagsModel describes the default_tags block.
+type DefaultTagsModel struct {
+	Tags types.Map `tfsdk:"tags"`
+}
+
+// IgnoreTagsModel describes the ignore_tags block.
+type IgnoreTagsModel struct {
+	Keys        types.Set `tfsdk:"keys"`
+	KeyPrefixes types.Set `tfsdk:"key_prefixes"`
 }
 
 func (p *BingoCloudProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -52,28 +109,154 @@ func (p *BingoCloudProvider) Schema(ctx context.Context, req provider.SchemaRequ
 		MarkdownDescription: "BingoCloud 私有云 Provider",
 		Attributes: map[string]schema.Attribute{
 			"endpoint": schema.StringAttribute{
-				MarkdownDescription: "BingoCloud API 端点地址",
+				MarkdownDescription: "BingoCloud API 端点地址，也可通过 BINGOCLOUD_ENDPOINT（或 AWS_ENDPOINT）环境变量设置",
 				Optional:            true,
 			},
 			"access_key": schema.StringAttribute{
-				MarkdownDescription: "访问密钥 ID",
+				MarkdownDescription: "访问密钥 ID，也可通过 BINGOCLOUD_ACCESS_KEY（或 AWS_ACCESS_KEY_ID）环境变量设置",
 				Optional:            true,
 				Sensitive:           true,
 			},
 			"secret_key": schema.StringAttribute{
-				MarkdownDescription: "访问密钥",
+				MarkdownDescription: "访问密钥，也可通过 BINGOCLOUD_SECRET_KEY（或 AWS_SECRET_ACCESS_KEY）环境变量设置",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"token": schema.StringAttribute{
+				MarkdownDescription: "临时凭证的会话令牌，与 access_key 和 secret_key 一起使用，也可通过 BINGOCLOUD_SESSION_TOKEN（或 AWS_SESSION_TOKEN）环境变量设置",
 				Optional:            true,
 				Sensitive:           true,
 			},
 			"region": schema.StringAttribute{
-				MarkdownDescription: "区域名称",
+				MarkdownDescription: "区域名称，也可通过 BINGOCLOUD_REGION 环境变量设置",
+				Optional:            true,
+			},
+			"disable_aws_env_fallback": schema.BoolAttribute{
+				MarkdownDescription: "不读取 AWS_* 环境变量，只使用 BINGOCLOUD_* 环境变量，未配置区域时直接使用默认区域，AWS_CA_BUNDLE 等证书变量也不会生效。与 AWS provider 在同一模块中使用时建议开启。默认 false",
+				Optional:            true,
+			},
+			"profile": schema.StringAttribute{
+				MarkdownDescription: "共享凭证文件中的 profile 名称，也可通过 BINGOCLOUD_PROFILE（或 AWS_PROFILE）环境变量设置。未配置 access_key 和 secret_key 时使用",
+				Optional:            true,
+			},
+			"shared_credentials_files": schema.ListAttribute{
+				MarkdownDescription: "共享凭证文件路径列表（~/.aws/credentials 格式），默认 ~/.aws/credentials，靠后的文件优先",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"shared_config_files": schema.ListAttribute{
+				MarkdownDescription: "共享配置文件路径列表（~/.aws/config 格式），默认 ~/.aws/config，靠后的文件优先",
+				ElementType:         types.StringType,
 				Optional:            true,
 			},
 			"insecure_skip_tls": schema.BoolAttribute{
-				MarkdownDescription: "跳过 TLS 证书验证（仅用于开发环境）",
+				MarkdownDescription: "跳过 TLS 证书验证（仅用于开发环境），也可通过 BINGOCLOUD_INSECURE 环境变量设置",
+				Optional:            true,
+			},
+			"ca_bundle": schema.StringAttribute{
+				MarkdownDescription: "用于验证 API 端点证书的 CA 证书，文件路径或 PEM 内容，在系统根证书的基础上追加",
+				Optional:            true,
+			},
+			"client_certificate": schema.StringAttribute{
+				MarkdownDescription: "双向 TLS 的客户端证书，文件路径或 PEM 内容，需同时配置 `client_key`",
+				Optional:            true,
+			},
+			"client_key": schema.StringAttribute{
+				MarkdownDescription: "双向 TLS 的客户端私钥，文件路径或 PEM 内容，需同时配置 `client_certificate`",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"http_proxy": schema.StringAttribute{
+				MarkdownDescription: "访问 API 端点使用的代理地址，如 `http://proxy.example.com:3128`，同时用于 HTTP 和 HTTPS 请求。未配置时使用 HTTP_PROXY 和 HTTPS_PROXY 环境变量",
+				Optional:            true,
+			},
+			"no_proxy": schema.StringAttribute{
+				MarkdownDescription: "不使用代理的主机列表，逗号分隔，格式与 NO_PROXY 环境变量相同。未配置时使用 NO_PROXY 环境变量",
+				Optional:            true,
+			},
+			"connect_timeout": schema.StringAttribute{
+				MarkdownDescription: "建立 TCP 连接和 TLS 握手的超时时间，如 `10s`，默认 30 秒建立连接、10 秒完成 TLS 握手",
+				Optional:            true,
+			},
+			"request_timeout": schema.StringAttribute{
+				MarkdownDescription: "单次 API 请求的超时时间（包括读取响应），如 `5m`，默认不限制",
+				Optional:            true,
+			},
+			"max_idle_conns": schema.Int64Attribute{
+				MarkdownDescription: "连接池中保留的最大空闲连接数，默认 100",
+				Optional:            true,
+			},
+			"max_retries": schema.Int64Attribute{
+				MarkdownDescription: "单个 API 请求遇到限流、临时错误或网络错误时的最大重试次数，0 表示不重试，默认 " + strconv.Itoa(conns.DefaultMaxRetries),
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"retry_mode": schema.StringAttribute{
+				MarkdownDescription: "重试模式：`standard` 按带抖动的指数退避重试；`adaptive` 在此基础上被限流后降低所有请求的发送速率。也可通过 BINGOCLOUD_RETRY_MODE（或 AWS_RETRY_MODE）环境变量设置，默认 `standard`",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(conns.RetryModeStandard, conns.RetryModeAdaptive),
+				},
+			},
+			"skip_credentials_validation": schema.BoolAttribute{
+				MarkdownDescription: "跳过 provider 配置阶段通过 DescribeAvailabilityZones 对 endpoint 和凭证的校验，适用于 endpoint 暂时不可达或凭证没有该接口权限的环境。默认 false",
+				Optional:            true,
+			},
+			"terminate_on_create_failure": schema.BoolAttribute{
+				MarkdownDescription: "实例已创建但未能进入运行状态时自动终止该实例。默认 false，实例保留在状态中并标记为 tainted，下次 apply 时替换",
 				Optional:            true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"assume_role": schema.SingleNestedBlock{
+				MarkdownDescription: "使用上面解析出的凭证通过 STS 兼容接口扮演角色，之后的请求都使用自动刷新的临时凭证",
+				Attributes: map[string]schema.Attribute{
+					"role_arn": schema.StringAttribute{
+						MarkdownDescription: "要扮演的角色 ARN",
+						Optional:            true,
+					},
+					"session_name": schema.StringAttribute{
+						MarkdownDescription: "会话名称，默认 `" + conns.DefaultAssumeRoleSessionName + "`",
+						Optional:            true,
+					},
+					"duration": schema.StringAttribute{
+						MarkdownDescription: "临时凭证有效期，如 `1h`、`30m`，默认 15 分钟",
+						Optional:            true,
+					},
+					"external_id": schema.StringAttribute{
+						MarkdownDescription: "扮演角色时传递的外部 ID",
+						Optional:            true,
+					},
+				},
+			},
+			"default_tags": schema.SingleNestedBlock{
+				MarkdownDescription: "所有支持标签的资源默认添加的标签",
+				Attributes: map[string]schema.Attribute{
+					"tags": schema.MapAttribute{
+						MarkdownDescription: "默认标签，资源 `tags` 中同名的键优先",
+						ElementType:         types.StringType,
+						Optional:            true,
+					},
+				},
+			},
+			"ignore_tags": schema.SingleNestedBlock{
+				MarkdownDescription: "读取资源时忽略的标签，通常用于平台组件自动添加的标签",
+				Attributes: map[string]schema.Attribute{
+					"keys": schema.SetAttribute{
+						MarkdownDescription: "需要忽略的标签键",
+						ElementType:         types.StringType,
+						Optional:            true,
+					},
+					"key_prefixes": schema.SetAttribute{
+						MarkdownDescription: "需要忽略的标签键前缀",
+						ElementType:         types.StringType,
+						Optional:            true,
+					},
+				},
+			},
+		},
 	}
 }
 
@@ -86,46 +269,137 @@ func (p *BingoCloudProvider) Configure(ctx context.Context, req provider.Configu
 		return
 	}
 
-	// 从环境变量获取配置（如果配置文件中未提供）
+	// 从环境变量获取配置（如果配置文件中未提供），BINGOCLOUD_* 优先于 AWS_*
+	awsEnvFallback := !data.DisableAWSEnvFallback.ValueBool()
+
 	endpoint := data.Endpoint.ValueString()
 	if endpoint == "" {
-		endpoint = os.Getenv("AWS_ENDPOINT")
+		endpoint = getEnv("BINGOCLOUD_ENDPOINT", "AWS_ENDPOINT", awsEnvFallback)
 	}
 
 	accessKey := data.AccessKey.ValueString()
 	if accessKey == "" {
-		accessKey = os.Getenv("AWS_ACCESS_KEY_ID")
+		accessKey = getEnv("BINGOCLOUD_ACCESS_KEY", "AWS_ACCESS_KEY_ID", awsEnvFallback)
 	}
 
 	secretKey := data.SecretKey.ValueString()
 	if secretKey == "" {
-		secretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		secretKey = getEnv("BINGOCLOUD_SECRET_KEY", "AWS_SECRET_ACCESS_KEY", awsEnvFallback)
 	}
 
+	// 区域未配置时使用 profile 中的区域，都没有时使用默认区域
 	region := data.Region.ValueString()
 	if region == "" {
-		region = "default"
+		region = os.Getenv("BINGOCLOUD_REGION")
+	}
+
+	token := data.Token.ValueString()
+	if token == "" {
+		token = getEnv("BINGOCLOUD_SESSION_TOKEN", "AWS_SESSION_TOKEN", awsEnvFallback)
+	}
+
+	var assumeRole *conns.AssumeRoleConfig
+	if data.AssumeRole != nil {
+		assumeRole = &conns.AssumeRoleConfig{
+			RoleARN:     data.AssumeRole.RoleARN.ValueString(),
+			SessionName: data.AssumeRole.SessionName.ValueString(),
+			ExternalID:  data.AssumeRole.ExternalID.ValueString(),
+		}
+		if assumeRole.RoleARN == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("assume_role").AtName("role_arn"),
+				"缺少 role_arn 配置",
+				"配置 assume_role 时必须提供 role_arn",
+			)
+		}
+		assumeRole.Duration = parseDuration(data.AssumeRole.Duration, path.Root("assume_role").AtName("duration"), &resp.Diagnostics)
 	}
 
 	insecureSkipTLS := data.InsecureSkipTLS.ValueBool()
+	if data.InsecureSkipTLS.IsNull() {
+		if v := os.Getenv("BINGOCLOUD_INSECURE"); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"无效的 BINGOCLOUD_INSECURE 环境变量",
+					"BINGOCLOUD_INSECURE 必须是布尔值（true 或 false）: "+err.Error(),
+				)
+			}
+			insecureSkipTLS = b
+		}
+	}
+
+	connectTimeout := parseDuration(data.ConnectTimeout, path.Root("connect_timeout"), &resp.Diagnostics)
+	requestTimeout := parseDuration(data.RequestTimeout, path.Root("request_timeout"), &resp.Diagnostics)
+
+	httpProxy := data.HTTPProxy.ValueString()
+	if httpProxy != "" {
+		if u, err := url.Parse(httpProxy); err != nil || u.Scheme == "" || u.Host == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("http_proxy"),
+				"无效的 http_proxy 配置",
+				"http_proxy 必须是包含协议和主机的 URL（如 http://proxy.example.com:3128）",
+			)
+		}
+	}
+
+	maxIdleConns := data.MaxIdleConns.ValueInt64()
+	if maxIdleConns < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_idle_conns"),
+			"无效的 max_idle_conns 配置",
+			"max_idle_conns 不能小于 0",
+		)
+	}
+
+	var maxRetries *int
+	if !data.MaxRetries.IsNull() {
+		v := int(data.MaxRetries.ValueInt64())
+		maxRetries = &v
+	}
+
+	retryMode := data.RetryMode.ValueString()
+	if retryMode == "" {
+		retryMode = getEnv("BINGOCLOUD_RETRY_MODE", "AWS_RETRY_MODE", awsEnvFallback)
+	}
+	if retryMode != "" && retryMode != conns.RetryModeStandard && retryMode != conns.RetryModeAdaptive {
+		resp.Diagnostics.AddError(
+			"无效的 retry_mode 配置",
+			"BINGOCLOUD_RETRY_MODE 必须是 "+conns.RetryModeStandard+" 或 "+conns.RetryModeAdaptive+"，当前为 "+retryMode,
+		)
+	}
+
+	profile := data.Profile.ValueString()
+	if profile == "" {
+		profile = getEnv("BINGOCLOUD_PROFILE", "AWS_PROFILE", awsEnvFallback)
+	}
+
+	var sharedCredentialsFiles, sharedConfigFiles []string
+	if !data.SharedCredentialsFiles.IsNull() && !data.SharedCredentialsFiles.IsUnknown() {
+		resp.Diagnostics.Append(data.SharedCredentialsFiles.ElementsAs(ctx, &sharedCredentialsFiles, false)...)
+	}
+	if !data.SharedConfigFiles.IsNull() && !data.SharedConfigFiles.IsUnknown() {
+		resp.Diagnostics.Append(data.SharedConfigFiles.ElementsAs(ctx, &sharedConfigFiles, false)...)
+	}
 
 	// 验证必需配置
 	if endpoint == "" {
 		resp.Diagnostics.AddError(
 			"缺少 Endpoint 配置",
-			"必须通过 provider 配置或 AWS_ENDPOINT 环境变量提供 endpoint",
+			"必须通过 provider 配置或 BINGOCLOUD_ENDPOINT（或 AWS_ENDPOINT）环境变量提供 endpoint",
 		)
 	}
-	if accessKey == "" {
+	// 静态密钥必须成对提供；都未提供时从共享凭证文件中解析
+	if accessKey == "" && secretKey != "" {
 		resp.Diagnostics.AddError(
 			"缺少 AccessKey 配置",
-			"必须通过 provider 配置或 AWS_ACCESS_KEY_ID 环境变量提供 access_key",
+			"配置了 secret_key 时必须通过 provider 配置或 BINGOCLOUD_ACCESS_KEY（或 AWS_ACCESS_KEY_ID）环境变量提供 access_key",
 		)
 	}
-	if secretKey == "" {
+	if secretKey == "" && accessKey != "" {
 		resp.Diagnostics.AddError(
 			"缺少 SecretKey 配置",
-			"必须通过 provider 配置或 AWS_SECRET_ACCESS_KEY 环境变量提供 secret_key",
+			"配置了 access_key 时必须通过 provider 配置或 BINGOCLOUD_SECRET_KEY（或 AWS_SECRET_ACCESS_KEY）环境变量提供 secret_key",
 		)
 	}
 
@@ -134,15 +408,74 @@ func (p *BingoCloudProvider) Configure(ctx context.Context, req provider.Configu
 	}
 
 	// 创建 BingoCloud 客户端
-	client, err := conns.NewBingoCloudClient(endpoint, accessKey, secretKey, region, insecureSkipTLS)
+	client, err := conns.NewBingoCloudClient(ctx, &conns.Config{
+		Endpoint:               endpoint,
+		AccessKey:              accessKey,
+		SecretKey:              secretKey,
+		Token:                  token,
+		Region:                 region,
+		InsecureSkipTLS:        insecureSkipTLS,
+		CABundle:               data.CABundle.ValueString(),
+		ClientCertificate:      data.ClientCertificate.ValueString(),
+		ClientKey:              data.ClientKey.ValueString(),
+		HTTPProxy:              httpProxy,
+		NoProxy:                data.NoProxy.ValueString(),
+		ConnectTimeout:         connectTimeout,
+		RequestTimeout:         requestTimeout,
+		MaxIdleConns:           int(maxIdleConns),
+		MaxRetries:             maxRetries,
+		RetryMode:              retryMode,
+		Profile:                profile,
+		SharedCredentialsFiles: sharedCredentialsFiles,
+		SharedConfigFiles:      sharedConfigFiles,
+		AssumeRole:             assumeRole,
+		DisableAWSEnv:          !awsEnvFallback,
+	})
+	var tlsErr *conns.TLSConfigError
+	if errors.As(err, &tlsErr) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root(tlsErr.Attribute),
+			"无效的 TLS 证书配置",
+			tlsErr.Attribute+" 配置无效: "+tlsErr.Err.Error(),
+		)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"无法创建 BingoCloud 客户端",
-			"创建客户端时发生错误: "+err.Error(),
+			"创建客户端时发生错误: "+err.Error()+"。请通过 access_key 和 secret_key、BINGOCLOUD_ACCESS_KEY 和 BINGOCLOUD_SECRET_KEY 环境变量，或共享凭证文件中的 profile 提供凭证",
 		)
 		return
 	}
 
+	// 在计划之前发现 endpoint 和凭证配置错误，而不是等到第一次创建资源时才失败
+	if !data.SkipCredentialsValidation.ValueBool() {
+		if err := client.ValidateCredentials(ctx); err != nil {
+			addValidationError(&resp.Diagnostics, endpoint, err)
+			return
+		}
+	}
+
+	client.TerminateOnCreateFailure = data.TerminateOnCreateFailure.ValueBool()
+
+	// 标签配置
+	client.DefaultTagsConfig = &tftags.DefaultConfig{}
+	if data.DefaultTags != nil && !data.DefaultTags.Tags.IsNull() && !data.DefaultTags.Tags.IsUnknown() {
+		resp.Diagnostics.Append(data.DefaultTags.Tags.ElementsAs(ctx, &client.DefaultTagsConfig.Tags, false)...)
+	}
+	client.IgnoreTagsConfig = &tftags.IgnoreConfig{}
+	if data.IgnoreTags != nil {
+		if !data.IgnoreTags.Keys.IsNull() && !data.IgnoreTags.Keys.IsUnknown() {
+			resp.Diagnostics.Append(data.IgnoreTags.Keys.ElementsAs(ctx, &client.IgnoreTagsConfig.Keys, false)...)
+		}
+		if !data.IgnoreTags.KeyPrefixes.IsNull() && !data.IgnoreTags.KeyPrefixes.IsUnknown() {
+			resp.Diagnostics.Append(data.IgnoreTags.KeyPrefixes.ElementsAs(ctx, &client.IgnoreTagsConfig.KeyPrefixes, false)...)
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// 将客户端传递给资源和数据源
 	resp.DataSourceData = client
 	resp.ResourceData = client
@@ -172,6 +505,75 @@ func (p *BingoCloudProvider) Actions(ctx context.Context) []func() action.Action
 	return []func() action.Action{}
 }
 
+// getEnv 按优先级读取环境变量：先读取 BINGOCLOUD_* 变量，未设置且允许回退时再读取 AWS_* 变量
+func getEnv(key, awsKey string, awsFallback bool) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	if awsFallback {
+		return os.Getenv(awsKey)
+	}
+	return ""
+}
+
+// addValidationError 按校验失败的类型添加诊断信息
+func addValidationError(diags *diag.Diagnostics, endpoint string, err error) {
+	const skipHint = "。如需跳过校验，请设置 skip_credentials_validation = true"
+
+	var validationErr *conns.ValidationError
+	if !errors.As(err, &validationErr) {
+		diags.AddError("BingoCloud 凭证校验失败", "校验凭证时发生错误: "+err.Error()+skipHint)
+		return
+	}
+
+	switch validationErr.Kind {
+	case conns.ValidationErrorDNS:
+		diags.AddAttributeError(
+			path.Root("endpoint"),
+			"无法解析 BingoCloud API 端点",
+			"无法解析 endpoint "+endpoint+" 的主机名，请检查地址拼写和 DNS 配置: "+err.Error(),
+		)
+	case conns.ValidationErrorTLS:
+		diags.AddAttributeError(
+			path.Root("endpoint"),
+			"BingoCloud API 端点 TLS 验证失败",
+			"与 endpoint "+endpoint+" 建立 TLS 连接失败，内部 CA 签发的证书请配置 ca_bundle，双向 TLS 请配置 client_certificate 和 client_key: "+err.Error(),
+		)
+	case conns.ValidationErrorAuth:
+		diags.AddError(
+			"BingoCloud 凭证无效",
+			"endpoint "+endpoint+" 拒绝了当前凭证，请检查 access_key、secret_key 和 token 是否正确且未过期: "+err.Error(),
+		)
+	case conns.ValidationErrorEndpoint:
+		diags.AddAttributeError(
+			path.Root("endpoint"),
+			"无法访问 BingoCloud API 端点",
+			"无法连接 endpoint "+endpoint+" 或其返回的不是 BingoCloud API 响应，请检查地址、端口、代理配置和网络连通性: "+err.Error()+skipHint,
+		)
+	default:
+		diags.AddError("BingoCloud 凭证校验失败", "调用 DescribeAvailabilityZones 校验凭证失败: "+err.Error()+skipHint)
+	}
+}
+
+// parseDuration 解析时间间隔类型的字符串属性，未配置时返回 0，格式无效时添加属性错误
+func parseDuration(value types.String, p path.Path, diags *diag.Diagnostics) time.Duration {
+	v := value.ValueString()
+	if v == "" {
+		return 0
+	}
+
+	duration, err := time.ParseDuration(v)
+	if err != nil || duration < 0 {
+		detail := "必须是有效的非负时间间隔（如 1h、30m）"
+		if err != nil {
+			detail += ": " + err.Error()
+		}
+		diags.AddAttributeError(p, "无效的时间间隔配置", p.String()+" "+detail)
+		return 0
+	}
+	return duration
+}
+
 func New(version string) func() provider.Provider {
 	return func() provider.Provider {
 		return &BingoCloudProvider{
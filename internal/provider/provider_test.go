@@ -41,3 +41,54 @@ provider "bingocloud" {
 }
 `
 }
+
+// TestGetEnv 测试优先读取 BINGOCLOUD_* 变量，禁用回退时不读取 AWS_* 变量
+func TestGetEnv(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         map[string]string
+		awsFallback bool
+		want        string
+	}{
+		{
+			name:        "bingocloud variable",
+			env:         map[string]string{"BINGOCLOUD_ENDPOINT": "http://bingocloud", "AWS_ENDPOINT": "http://aws"},
+			awsFallback: true,
+			want:        "http://bingocloud",
+		},
+		{
+			name:        "aws fallback",
+			env:         map[string]string{"AWS_ENDPOINT": "http://aws"},
+			awsFallback: true,
+			want:        "http://aws",
+		},
+		{
+			name:        "aws fallback disabled",
+			env:         map[string]string{"AWS_ENDPOINT": "http://aws"},
+			awsFallback: false,
+			want:        "",
+		},
+		{
+			name:        "bingocloud variable with fallback disabled",
+			env:         map[string]string{"BINGOCLOUD_ENDPOINT": "http://bingocloud", "AWS_ENDPOINT": "http://aws"},
+			awsFallback: false,
+			want:        "http://bingocloud",
+		},
+		{
+			name:        "not set",
+			awsFallback: true,
+			want:        "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BINGOCLOUD_ENDPOINT", tt.env["BINGOCLOUD_ENDPOINT"])
+			t.Setenv("AWS_ENDPOINT", tt.env["AWS_ENDPOINT"])
+
+			if got := getEnv("BINGOCLOUD_ENDPOINT", "AWS_ENDPOINT", tt.awsFallback); got != tt.want {
+				t.Errorf("应读取到 %q，得到 %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,557 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package ec2
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/mulei1288/terraform-provider-bingocloud/internal/conns"
+	tftags "github.com/mulei1288/terraform-provider-bingocloud/internal/tags"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/awserr"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/service/ec2"
+)
+
+// 确保实现了必需的接口
+var _ resource.Resource = &EipResource{}
+var _ resource.ResourceWithImportState = &EipResource{}
+var _ resource.ResourceWithModifyPlan = &EipResource{}
+
+// 弹性 IP 相关的错误码
+const (
+	errCodeInvalidAllocationIDNotFound  = "InvalidAllocationID.NotFound"
+	errCodeInvalidAddressNotFound       = "InvalidAddress.NotFound"
+	errCodeInvalidAssociationIDNotFound = "InvalidAssociationID.NotFound"
+	errCodeInvalidIPAddressInUse        = "InvalidIPAddress.InUse"
+)
+
+// 默认超时时间
+const (
+	eipCreateTimeout = 5 * time.Minute
+	eipUpdateTimeout = 5 * time.Minute
+	eipDeleteTimeout = 10 * time.Minute
+)
+
+// allocationIDPrefix VPC 弹性 IP 分配 ID 的前缀，标准类型的弹性 IP 没有分配 ID，以公网 IP 标识
+const allocationIDPrefix = "eipalloc-"
+
+// EipResource 定义弹性 IP 资源实现
+type EipResource struct {
+	client *conns.BingoCloudClient
+}
+
+// EipResourceModel 描述弹性 IP 资源数据模型
+type EipResourceModel struct {
+	// 可选参数
+	Domain types.String `tfsdk:"domain"`
+	Tags   types.Map    `tfsdk:"tags"`
+
+	// 计算属性
+	ID                 types.String `tfsdk:"id"`
+	AllocationID       types.String `tfsdk:"allocation_id"`
+	PublicIP           types.String `tfsdk:"public_ip"`
+	AssociationID      types.String `tfsdk:"association_id"`
+	InstanceID         types.String `tfsdk:"instance_id"`
+	NetworkInterfaceID types.String `tfsdk:"network_interface_id"`
+	PrivateIP          types.String `tfsdk:"private_ip"`
+	TagsAll            types.Map    `tfsdk:"tags_all"`
+
+	// 超时配置
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// NewEipResource 创建新的弹性 IP 资源
+func NewEipResource() resource.Resource {
+	return &EipResource{}
+}
+
+// Metadata 返回资源类型名称
+func (r *EipResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_eip"
+}
+
+// Configure 配置资源，接收 Provider 传递的客户端
+func (r *EipResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*conns.BingoCloudClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"意外的资源配置类型",
+			fmt.Sprintf("期望 *conns.BingoCloudClient，得到: %T。请向 provider 开发者报告此问题。", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+// Schema 定义资源的属性架构
+func (r *EipResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "管理 BingoCloud 弹性 IP。弹性 IP 与实例的绑定通过 `bingocloud_eip_association` 管理，" +
+			"删除弹性 IP 时会先解除绑定再释放",
+
+		Attributes: map[string]schema.Attribute{
+			// 可选参数
+			"domain": schema.StringAttribute{
+				MarkdownDescription: "弹性 IP 类型：`vpc` 或 `standard`，不配置时使用平台默认类型，修改时重新分配弹性 IP",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(ec2.DomainTypeVpc, ec2.DomainTypeStandard),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tags": tftags.TagsAttribute(),
+
+			// 计算属性（只读）
+			"id": schema.StringAttribute{
+				MarkdownDescription: "弹性 IP 标识，VPC 类型为分配 ID，标准类型为公网 IP",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"allocation_id": schema.StringAttribute{
+				MarkdownDescription: "分配 ID，标准类型的弹性 IP 为空",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"public_ip": schema.StringAttribute{
+				MarkdownDescription: "公网 IP 地址",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"association_id": schema.StringAttribute{
+				MarkdownDescription: "当前绑定的关联 ID",
+				Computed:            true,
+			},
+			"instance_id": schema.StringAttribute{
+				MarkdownDescription: "当前绑定的实例 ID",
+				Computed:            true,
+			},
+			"network_interface_id": schema.StringAttribute{
+				MarkdownDescription: "当前绑定的网卡 ID",
+				Computed:            true,
+			},
+			"private_ip": schema.StringAttribute{
+				MarkdownDescription: "当前绑定的私有 IP 地址",
+				Computed:            true,
+			},
+			"tags_all": tftags.TagsAllAttribute(),
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// Create 分配弹性 IP
+func (r *EipResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan EipResourceModel
+
+	// 读取 Terraform 计划数据
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, eipCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	conn := r.client.EC2Client()
+
+	input := &ec2.AllocateAddressInput{}
+	if !plan.Domain.IsNull() && !plan.Domain.IsUnknown() {
+		input.Domain = aws.String(plan.Domain.ValueString())
+	}
+
+	tflog.Debug(ctx, "分配 BingoCloud 弹性 IP", map[string]interface{}{
+		"domain": plan.Domain.ValueString(),
+	})
+
+	result, err := conn.AllocateAddressWithContext(ctx, input)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"分配弹性 IP 失败",
+			"无法分配弹性 IP: "+err.Error(),
+		)
+		return
+	}
+
+	id := aws.StringValue(result.AllocationId)
+	if id == "" {
+		id = aws.StringValue(result.PublicIp)
+	}
+	plan.ID = types.StringValue(id)
+
+	// 立即保存弹性 IP 标识，后续步骤失败时资源会被标记为 tainted
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), plan.ID)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// 标签：创建后通过 CreateTags 添加，标准类型的弹性 IP 没有分配 ID，不支持标签
+	tags, diags := tagsMapValue(ctx, plan.TagsAll)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if len(tags) > 0 {
+		if !strings.HasPrefix(id, allocationIDPrefix) {
+			resp.Diagnostics.AddError(
+				"设置弹性 IP 标签失败",
+				"标准类型的弹性 IP "+id+" 不支持标签，请使用 VPC 类型或移除 tags",
+			)
+			return
+		}
+		if err := updateTags(ctx, conn, id, nil, tags); err != nil {
+			resp.Diagnostics.AddError(
+				"设置弹性 IP 标签失败",
+				"无法为弹性 IP "+id+" 设置标签: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	address, err := findAddressByID(ctx, conn, id)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"读取弹性 IP 详情失败",
+			"弹性 IP 分配成功但无法读取详细信息: "+err.Error(),
+		)
+		return
+	}
+
+	flattenAddress(address, &plan)
+
+	tflog.Trace(ctx, "分配弹性 IP 成功")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read 读取弹性 IP 状态
+func (r *EipResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state EipResourceModel
+
+	// 读取 Terraform 状态数据
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	address, err := findAddressByID(ctx, r.client.EC2Client(), state.ID.ValueString())
+	if err != nil {
+		if isAddressNotFoundError(err) {
+			// 弹性 IP 已释放，从状态中移除
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"读取弹性 IP 失败",
+			"无法读取弹性 IP "+state.ID.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	flattenAddress(address, &state)
+
+	// 标签：按 provider 的默认标签和忽略标签配置拆分到 tags 和 tags_all
+	tagsValue, tagsAllValue, diags := tftags.Flatten(ctx, tagsToMap(address.Tags), state.Tags, r.client.DefaultTagsConfig, r.client.IgnoreTagsConfig)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	state.Tags = tagsValue
+	state.TagsAll = tagsAllValue
+
+	// 保存更新后的状态
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update 更新弹性 IP 的标签
+func (r *EipResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state EipResourceModel
+
+	// 读取计划数据和当前状态
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, eipUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	conn := r.client.EC2Client()
+	id := state.ID.ValueString()
+
+	// 标签：按包含默认标签的 tags_all 比较
+	if !plan.TagsAll.Equal(state.TagsAll) {
+		oldTags, diags := tagsMapValue(ctx, state.TagsAll)
+		resp.Diagnostics.Append(diags...)
+		newTags, diags := tagsMapValue(ctx, plan.TagsAll)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if !strings.HasPrefix(id, allocationIDPrefix) {
+			resp.Diagnostics.AddError(
+				"更新弹性 IP 标签失败",
+				"标准类型的弹性 IP "+id+" 不支持标签，请使用 VPC 类型或移除 tags",
+			)
+			return
+		}
+		if err := updateTags(ctx, conn, id, oldTags, newTags); err != nil {
+			resp.Diagnostics.AddError(
+				"更新弹性 IP 标签失败",
+				"无法更新弹性 IP "+id+" 的标签: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	// 重新读取弹性 IP 以刷新绑定信息
+	address, err := findAddressByID(ctx, conn, id)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"读取弹性 IP 详情失败",
+			"弹性 IP 更新成功但无法读取详细信息: "+err.Error(),
+		)
+		return
+	}
+
+	flattenAddress(address, &plan)
+
+	tflog.Trace(ctx, "更新弹性 IP 成功")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete 释放弹性 IP，仍有绑定时先解除绑定
+func (r *EipResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state EipResourceModel
+
+	// 读取 Terraform 状态数据
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, eipDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	conn := r.client.EC2Client()
+	id := state.ID.ValueString()
+
+	address, err := findAddressByID(ctx, conn, id)
+	if err != nil {
+		if isAddressNotFoundError(err) {
+			return
+		}
+		resp.Diagnostics.AddError(
+			"释放弹性 IP 失败",
+			"无法读取弹性 IP "+id+": "+err.Error(),
+		)
+		return
+	}
+
+	if aws.StringValue(address.AssociationId) != "" || aws.StringValue(address.InstanceId) != "" {
+		if err := disassociateAddress(ctx, conn, address); err != nil {
+			resp.Diagnostics.AddError(
+				"释放弹性 IP 失败",
+				"无法解除弹性 IP "+id+" 的绑定: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	input := &ec2.ReleaseAddressInput{}
+	if strings.HasPrefix(id, allocationIDPrefix) {
+		input.AllocationId = aws.String(id)
+	} else {
+		input.PublicIp = aws.String(id)
+	}
+
+	tflog.Debug(ctx, "释放 BingoCloud 弹性 IP", map[string]interface{}{
+		"id": id,
+	})
+
+	// 刚解除绑定时 API 可能仍返回 InvalidIPAddress.InUse，等待后重试，直到删除超时
+	err = retryWhenErrorCode(ctx, errCodeInvalidIPAddressInUse, func() error {
+		_, err := conn.ReleaseAddressWithContext(ctx, input)
+		return err
+	})
+	if err != nil && !isAddressNotFoundError(err) {
+		resp.Diagnostics.AddError(
+			"释放弹性 IP 失败",
+			"无法释放弹性 IP "+id+": "+waitErrorDetail(ctx, "删除", deleteTimeout, err, addressLastState(conn, id)),
+		)
+		return
+	}
+
+	tflog.Trace(ctx, "释放弹性 IP 成功")
+}
+
+// ModifyPlan 调整计划：计算 tags_all
+func (r *EipResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// 销毁时无需调整
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	// 合并 provider 的默认标签
+	if r.client != nil {
+		resp.Diagnostics.Append(tftags.ModifyPlan(ctx, r.client.DefaultTagsConfig, r.client.IgnoreTagsConfig, &resp.Plan)...)
+	}
+}
+
+// ImportState 支持通过分配 ID 或公网 IP（标准类型）导入资源
+func (r *EipResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// findAddressByID 根据分配 ID 或公网 IP 查询弹性 IP，弹性 IP 不存在时返回 InvalidAddress.NotFound 错误
+func findAddressByID(ctx context.Context, conn *ec2.EC2, id string) (*ec2.Address, error) {
+	if !strings.HasPrefix(id, allocationIDPrefix) {
+		return findAddressByPublicIP(ctx, conn, id)
+	}
+
+	return findAddress(ctx, conn, &ec2.DescribeAddressesInput{
+		AllocationIds: []*string{aws.String(id)},
+	}, id)
+}
+
+// findAddressByPublicIP 根据公网 IP 查询弹性 IP，弹性 IP 不存在时返回 InvalidAddress.NotFound 错误
+func findAddressByPublicIP(ctx context.Context, conn *ec2.EC2, publicIP string) (*ec2.Address, error) {
+	return findAddress(ctx, conn, &ec2.DescribeAddressesInput{
+		PublicIps: []*string{aws.String(publicIP)},
+	}, publicIP)
+}
+
+// findAddressByAssociationID 根据关联 ID 查询弹性 IP，弹性 IP 不存在时返回 InvalidAddress.NotFound 错误
+func findAddressByAssociationID(ctx context.Context, conn *ec2.EC2, associationID string) (*ec2.Address, error) {
+	return findAddress(ctx, conn, &ec2.DescribeAddressesInput{
+		Filters: []*ec2.Filter{
+			{Name: aws.String("association-id"), Values: []*string{aws.String(associationID)}},
+		},
+	}, associationID)
+}
+
+// findAddress 查询单个弹性 IP，结果为空时返回 InvalidAddress.NotFound 错误
+func findAddress(ctx context.Context, conn *ec2.EC2, input *ec2.DescribeAddressesInput, id string) (*ec2.Address, error) {
+	result, err := conn.DescribeAddressesWithContext(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Addresses) == 0 || result.Addresses[0] == nil {
+		return nil, awserr.New(errCodeInvalidAddressNotFound, "弹性 IP "+id+" 不存在", nil)
+	}
+
+	return result.Addresses[0], nil
+}
+
+// isAddressNotFoundError 判断是否为弹性 IP 不存在的错误
+func isAddressNotFoundError(err error) bool {
+	return isErrorCode(err, errCodeInvalidAddressNotFound) || isErrorCode(err, errCodeInvalidAllocationIDNotFound)
+}
+
+// addressLastState 返回查询弹性 IP 最后状态的函数，弹性 IP 没有状态字段，返回绑定情况
+func addressLastState(conn *ec2.EC2, id string) func(context.Context) string {
+	return func(ctx context.Context) string {
+		address, err := findAddressByID(ctx, conn, id)
+		if err != nil {
+			if isAddressNotFoundError(err) {
+				return "released"
+			}
+			return "unknown"
+		}
+		if instanceID := aws.StringValue(address.InstanceId); instanceID != "" {
+			return "associated(" + instanceID + ")"
+		}
+		return "unassociated"
+	}
+}
+
+// disassociateAddress 解除弹性 IP 的绑定，VPC 类型按关联 ID 解除，标准类型按公网 IP 解除
+func disassociateAddress(ctx context.Context, conn *ec2.EC2, address *ec2.Address) error {
+	input := &ec2.DisassociateAddressInput{}
+	if associationID := aws.StringValue(address.AssociationId); associationID != "" {
+		input.AssociationId = aws.String(associationID)
+	} else {
+		input.PublicIp = address.PublicIp
+	}
+
+	tflog.Debug(ctx, "解除 BingoCloud 弹性 IP 绑定", map[string]interface{}{
+		"public_ip":      aws.StringValue(address.PublicIp),
+		"association_id": aws.StringValue(address.AssociationId),
+		"instance_id":    aws.StringValue(address.InstanceId),
+	})
+
+	_, err := conn.DisassociateAddressWithContext(ctx, input)
+	if err != nil && !isErrorCode(err, errCodeInvalidAssociationIDNotFound) && !isAddressNotFoundError(err) {
+		return err
+	}
+	return nil
+}
+
+// flattenAddress 将 API 返回的弹性 IP 信息写入模型中的计算属性
+func flattenAddress(address *ec2.Address, model *EipResourceModel) {
+	// 部分环境不返回类型，保持配置的值
+	if v := aws.StringValue(address.Domain); v != "" || model.Domain.IsUnknown() {
+		model.Domain = stringValueOrNull(address.Domain)
+	}
+	model.AllocationID = stringValueOrNull(address.AllocationId)
+	model.PublicIP = types.StringValue(aws.StringValue(address.PublicIp))
+	model.AssociationID = stringValueOrNull(address.AssociationId)
+	model.InstanceID = stringValueOrNull(address.InstanceId)
+	model.NetworkInterfaceID = stringValueOrNull(address.NetworkInterfaceId)
+	model.PrivateIP = stringValueOrNull(address.PrivateIpAddress)
+}
@@ -0,0 +1,451 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package ec2
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/mulei1288/terraform-provider-bingocloud/internal/conns"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/awserr"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/service/ec2"
+)
+
+// 确保实现了必需的接口
+var _ resource.Resource = &EipAssociationResource{}
+var _ resource.ResourceWithImportState = &EipAssociationResource{}
+var _ resource.ResourceWithValidateConfig = &EipAssociationResource{}
+
+// associationIDPrefix VPC 弹性 IP 关联 ID 的前缀，标准类型的弹性 IP 没有关联 ID，以公网 IP 标识
+const associationIDPrefix = "eipassoc-"
+
+// 默认超时时间
+const (
+	eipAssociationCreateTimeout = 5 * time.Minute
+	eipAssociationUpdateTimeout = 5 * time.Minute
+	eipAssociationDeleteTimeout = 5 * time.Minute
+)
+
+// EipAssociationResource 定义弹性 IP 绑定资源实现
+type EipAssociationResource struct {
+	client *conns.BingoCloudClient
+}
+
+// EipAssociationResourceModel 描述弹性 IP 绑定资源数据模型
+type EipAssociationResourceModel struct {
+	// 可选参数
+	AllocationID       types.String `tfsdk:"allocation_id"`
+	PublicIP           types.String `tfsdk:"public_ip"`
+	InstanceID         types.String `tfsdk:"instance_id"`
+	NetworkInterfaceID types.String `tfsdk:"network_interface_id"`
+	PrivateIPAddress   types.String `tfsdk:"private_ip_address"`
+	AllowReassociation types.Bool   `tfsdk:"allow_reassociation"`
+
+	// 计算属性
+	ID types.String `tfsdk:"id"`
+
+	// 超时配置
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// NewEipAssociationResource 创建新的弹性 IP 绑定资源
+func NewEipAssociationResource() resource.Resource {
+	return &EipAssociationResource{}
+}
+
+// Metadata 返回资源类型名称
+func (r *EipAssociationResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_eip_association"
+}
+
+// Configure 配置资源，接收 Provider 传递的客户端
+func (r *EipAssociationResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*conns.BingoCloudClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"意外的资源配置类型",
+			fmt.Sprintf("期望 *conns.BingoCloudClient，得到: %T。请向 provider 开发者报告此问题。", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+// Schema 定义资源的属性架构
+func (r *EipAssociationResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "将 BingoCloud 弹性 IP 绑定到实例或网卡。修改绑定目标时原地重新绑定，" +
+			"不会先解除绑定，适用于蓝绿切换",
+
+		Attributes: map[string]schema.Attribute{
+			// 可选参数
+			"allocation_id": schema.StringAttribute{
+				MarkdownDescription: "VPC 类型弹性 IP 的分配 ID，与 `public_ip` 二选一，修改时重建绑定",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"public_ip": schema.StringAttribute{
+				MarkdownDescription: "标准类型弹性 IP 的公网 IP，与 `allocation_id` 二选一，修改时重建绑定",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"instance_id": schema.StringAttribute{
+				MarkdownDescription: "绑定的实例 ID，与 `network_interface_id` 至少配置一个，修改时原地重新绑定",
+				Optional:            true,
+				Computed:            true,
+			},
+			"network_interface_id": schema.StringAttribute{
+				MarkdownDescription: "绑定的网卡 ID，修改时原地重新绑定",
+				Optional:            true,
+				Computed:            true,
+			},
+			"private_ip_address": schema.StringAttribute{
+				MarkdownDescription: "绑定的网卡私有 IP 地址，不配置时使用主私有 IP，修改时原地重新绑定",
+				Optional:            true,
+				Computed:            true,
+			},
+			"allow_reassociation": schema.BoolAttribute{
+				MarkdownDescription: "创建时弹性 IP 已绑定到其它目标的情况下是否允许重新绑定，默认不允许。修改绑定目标时总是允许",
+				Optional:            true,
+			},
+
+			// 计算属性（只读）
+			"id": schema.StringAttribute{
+				MarkdownDescription: "绑定 ID，VPC 类型为关联 ID，标准类型为公网 IP。重新绑定后会变化",
+				Computed:            true,
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// Create 绑定弹性 IP
+func (r *EipAssociationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan EipAssociationResourceModel
+
+	// 读取 Terraform 计划数据
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, eipAssociationCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	conn := r.client.EC2Client()
+
+	id, err := associateAddress(ctx, conn, plan, plan.AllowReassociation.ValueBool())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"绑定弹性 IP 失败",
+			"无法绑定弹性 IP: "+err.Error(),
+		)
+		return
+	}
+	plan.ID = types.StringValue(id)
+
+	address, err := findAddressByAssociation(ctx, conn, id)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"读取弹性 IP 绑定详情失败",
+			"弹性 IP 绑定成功但无法读取详细信息: "+err.Error(),
+		)
+		return
+	}
+
+	flattenAddressAssociation(address, &plan)
+
+	tflog.Trace(ctx, "绑定弹性 IP 成功")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read 读取弹性 IP 绑定状态，绑定已解除或弹性 IP 已重新绑定到其它目标时从状态中移除
+func (r *EipAssociationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state EipAssociationResourceModel
+
+	// 读取 Terraform 状态数据
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	address, err := findAddressByAssociation(ctx, r.client.EC2Client(), state.ID.ValueString())
+	if err != nil {
+		if isAddressNotFoundError(err) {
+			// 绑定不存在，从状态中移除
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"读取弹性 IP 绑定失败",
+			"无法读取弹性 IP 绑定 "+state.ID.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	flattenAddressAssociation(address, &state)
+
+	// 保存更新后的状态
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update 绑定目标变化时原地重新绑定弹性 IP
+func (r *EipAssociationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state EipAssociationResourceModel
+
+	// 读取计划数据和当前状态
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, eipAssociationUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	conn := r.client.EC2Client()
+	id := state.ID.ValueString()
+
+	// 未配置的绑定目标在计划中为未知值，只比较已配置的目标
+	targetChanged := false
+	for _, target := range []struct{ plan, state types.String }{
+		{plan.InstanceID, state.InstanceID},
+		{plan.NetworkInterfaceID, state.NetworkInterfaceID},
+		{plan.PrivateIPAddress, state.PrivateIPAddress},
+	} {
+		if !target.plan.IsUnknown() && !target.plan.Equal(target.state) {
+			targetChanged = true
+		}
+	}
+
+	if targetChanged {
+		tflog.Debug(ctx, "重新绑定 BingoCloud 弹性 IP", map[string]interface{}{
+			"association_id":       id,
+			"instance_id":          plan.InstanceID.ValueString(),
+			"network_interface_id": plan.NetworkInterfaceID.ValueString(),
+		})
+
+		// 弹性 IP 当前绑定在原目标上，重新绑定必须允许
+		newID, err := associateAddress(ctx, conn, plan, true)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"重新绑定弹性 IP 失败",
+				"无法重新绑定弹性 IP "+id+": "+err.Error(),
+			)
+			return
+		}
+		id = newID
+	}
+	plan.ID = types.StringValue(id)
+
+	address, err := findAddressByAssociation(ctx, conn, id)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"读取弹性 IP 绑定详情失败",
+			"弹性 IP 绑定更新成功但无法读取详细信息: "+err.Error(),
+		)
+		return
+	}
+
+	flattenAddressAssociation(address, &plan)
+
+	tflog.Trace(ctx, "更新弹性 IP 绑定成功")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete 解除弹性 IP 绑定
+func (r *EipAssociationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state EipAssociationResourceModel
+
+	// 读取 Terraform 状态数据
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, eipAssociationDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	conn := r.client.EC2Client()
+	id := state.ID.ValueString()
+
+	address, err := findAddressByAssociation(ctx, conn, id)
+	if err != nil {
+		if isAddressNotFoundError(err) {
+			return
+		}
+		resp.Diagnostics.AddError(
+			"解除弹性 IP 绑定失败",
+			"无法读取弹性 IP 绑定 "+id+": "+err.Error(),
+		)
+		return
+	}
+
+	if err := disassociateAddress(ctx, conn, address); err != nil {
+		resp.Diagnostics.AddError(
+			"解除弹性 IP 绑定失败",
+			"无法解除弹性 IP 绑定 "+id+": "+err.Error(),
+		)
+		return
+	}
+
+	tflog.Trace(ctx, "解除弹性 IP 绑定成功")
+}
+
+// ValidateConfig 校验弹性 IP 和绑定目标
+func (r *EipAssociationResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config EipAssociationResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.AllocationID.IsUnknown() || config.PublicIP.IsUnknown() {
+		return
+	}
+	if config.AllocationID.IsNull() == config.PublicIP.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("allocation_id"),
+			"弹性 IP 配置错误",
+			"allocation_id 和 public_ip 必须且只能配置其中一个",
+		)
+	}
+
+	if config.InstanceID.IsNull() && config.NetworkInterfaceID.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("instance_id"),
+			"缺少绑定目标",
+			"instance_id 和 network_interface_id 至少需要配置一个",
+		)
+	}
+}
+
+// ImportState 支持通过关联 ID 或公网 IP（标准类型）导入资源
+func (r *EipAssociationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// associateAddress 将弹性 IP 绑定到模型中配置的目标，返回绑定 ID
+// 计划中的未知值表示未配置，不传给 API
+func associateAddress(ctx context.Context, conn *ec2.EC2, model EipAssociationResourceModel, allowReassociation bool) (string, error) {
+	input := &ec2.AssociateAddressInput{
+		AllowReassociation: aws.Bool(allowReassociation),
+	}
+	if v := model.AllocationID.ValueString(); v != "" {
+		input.AllocationId = aws.String(v)
+	} else {
+		input.PublicIp = aws.String(model.PublicIP.ValueString())
+	}
+	if v := model.InstanceID.ValueString(); v != "" {
+		input.InstanceId = aws.String(v)
+	}
+	if v := model.NetworkInterfaceID.ValueString(); v != "" {
+		input.NetworkInterfaceId = aws.String(v)
+	}
+	if v := model.PrivateIPAddress.ValueString(); v != "" {
+		input.PrivateIpAddress = aws.String(v)
+	}
+
+	tflog.Debug(ctx, "绑定 BingoCloud 弹性 IP", map[string]interface{}{
+		"allocation_id":        aws.StringValue(input.AllocationId),
+		"public_ip":            aws.StringValue(input.PublicIp),
+		"instance_id":          aws.StringValue(input.InstanceId),
+		"network_interface_id": aws.StringValue(input.NetworkInterfaceId),
+	})
+
+	result, err := conn.AssociateAddressWithContext(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	if id := aws.StringValue(result.AssociationId); id != "" {
+		return id, nil
+	}
+	return model.PublicIP.ValueString(), nil
+}
+
+// findAddressByAssociation 根据绑定 ID 查询弹性 IP，标准类型的弹性 IP 未绑定实例时返回 InvalidAddress.NotFound 错误
+func findAddressByAssociation(ctx context.Context, conn *ec2.EC2, id string) (*ec2.Address, error) {
+	if strings.HasPrefix(id, associationIDPrefix) {
+		return findAddressByAssociationID(ctx, conn, id)
+	}
+
+	address, err := findAddressByPublicIP(ctx, conn, id)
+	if err != nil {
+		return nil, err
+	}
+	if aws.StringValue(address.InstanceId) == "" {
+		return nil, awserr.New(errCodeInvalidAddressNotFound, "弹性 IP "+id+" 未绑定", nil)
+	}
+	return address, nil
+}
+
+// flattenAddressAssociation 将 API 返回的弹性 IP 绑定信息写入模型
+// API 没有返回的绑定目标保持配置的值
+func flattenAddressAssociation(address *ec2.Address, model *EipAssociationResourceModel) {
+	model.AllocationID = stringValueOrNull(address.AllocationId)
+	model.PublicIP = types.StringValue(aws.StringValue(address.PublicIp))
+
+	for _, target := range []struct {
+		value *types.String
+		api   *string
+	}{
+		{&model.InstanceID, address.InstanceId},
+		{&model.NetworkInterfaceID, address.NetworkInterfaceId},
+		{&model.PrivateIPAddress, address.PrivateIpAddress},
+	} {
+		if aws.StringValue(target.api) != "" || target.value.IsUnknown() {
+			*target.value = stringValueOrNull(target.api)
+		}
+	}
+}
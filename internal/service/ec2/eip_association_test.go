@@ -0,0 +1,96 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package ec2_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/mulei1288/terraform-provider-bingocloud/internal/acctest"
+)
+
+// testAccEipAssociationConfig 生成弹性 IP 绑定资源的测试配置，target 为绑定的实例（blue 或 green）
+func testAccEipAssociationConfig(name, target string) string {
+	return acctest.ProviderConfig() + fmt.Sprintf(`
+resource "bingocloud_instance" "blue" {
+  image_id      = %[1]q
+  instance_type = "m1.small"
+  subnet_id     = %[2]q
+  password      = "Test@123456"
+  instance_name = "%[3]s-blue"
+
+  block_device_mappings = [
+    {
+      volume_size = 20
+      volume_type = "standard"
+    }
+  ]
+}
+
+resource "bingocloud_instance" "green" {
+  image_id      = %[1]q
+  instance_type = "m1.small"
+  subnet_id     = %[2]q
+  password      = "Test@123456"
+  instance_name = "%[3]s-green"
+
+  block_device_mappings = [
+    {
+      volume_size = 20
+      volume_type = "standard"
+    }
+  ]
+}
+
+resource "bingocloud_eip" "test" {
+  domain = "vpc"
+}
+
+resource "bingocloud_eip_association" "test" {
+  allocation_id = bingocloud_eip.test.allocation_id
+  instance_id   = bingocloud_instance.%[4]s.id
+}
+`, os.Getenv("BINGOCLOUD_TEST_AMI"), os.Getenv("BINGOCLOUD_TEST_SUBNET"), name, target)
+}
+
+// TestAccEipAssociationResource_basic 测试弹性 IP 绑定、原地切换到另一个实例和导入
+func TestAccEipAssociationResource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// 绑定到 blue 实例
+			{
+				Config: testAccEipAssociationConfig("test-eip-assoc", "blue"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("bingocloud_eip_association.test", "instance_id", "bingocloud_instance.blue", "id"),
+					resource.TestCheckResourceAttrPair("bingocloud_eip_association.test", "public_ip", "bingocloud_eip.test", "public_ip"),
+					resource.TestCheckResourceAttrSet("bingocloud_eip_association.test", "id"),
+				),
+			},
+			// 原地切换到 green 实例
+			{
+				Config: testAccEipAssociationConfig("test-eip-assoc", "green"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("bingocloud_eip_association.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("bingocloud_eip_association.test", "instance_id", "bingocloud_instance.green", "id"),
+				),
+			},
+			// 导入状态测试
+			{
+				ResourceName:            "bingocloud_eip_association.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"allow_reassociation"},
+			},
+		},
+	})
+}
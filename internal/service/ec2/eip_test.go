@@ -0,0 +1,59 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package ec2_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/mulei1288/terraform-provider-bingocloud/internal/acctest"
+)
+
+// testAccEipConfig 生成弹性 IP 资源的测试配置
+func testAccEipConfig(name string) string {
+	return acctest.ProviderConfig() + fmt.Sprintf(`
+resource "bingocloud_eip" "test" {
+  domain = "vpc"
+
+  tags = {
+    Name = %[1]q
+  }
+}
+`, name)
+}
+
+// TestAccEipResource_basic 测试弹性 IP 资源的分配、标签更新和导入
+func TestAccEipResource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// 创建和读取测试
+			{
+				Config: testAccEipConfig("test-eip"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("bingocloud_eip.test", "domain", "vpc"),
+					resource.TestCheckResourceAttr("bingocloud_eip.test", "tags.Name", "test-eip"),
+					resource.TestCheckResourceAttrPair("bingocloud_eip.test", "id", "bingocloud_eip.test", "allocation_id"),
+					resource.TestCheckResourceAttrSet("bingocloud_eip.test", "public_ip"),
+					resource.TestCheckNoResourceAttr("bingocloud_eip.test", "association_id"),
+				),
+			},
+			// 原地修改标签
+			{
+				Config: testAccEipConfig("test-eip-updated"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("bingocloud_eip.test", "tags.Name", "test-eip-updated"),
+				),
+			},
+			// 导入状态测试
+			{
+				ResourceName:      "bingocloud_eip.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
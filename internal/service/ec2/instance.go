@@ -6,25 +6,45 @@ package ec2
 import (
 	"context"
 	"fmt"
+	"sort"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/mulei1288/terraform-provider-bingocloud/internal/conns"
+	tftags "github.com/mulei1288/terraform-provider-bingocloud/internal/tags"
 	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws"
 	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/awserr"
-	"github.com/mulei1288/terraform-provider-bingocloud/internal/conns"
 	"gitlab.bingosoft.net/bingokube/aws-sdk-go/service/ec2"
 )
 
 // 确保实现了必需的接口
 var _ resource.Resource = &InstanceResource{}
 var _ resource.ResourceWithImportState = &InstanceResource{}
+var _ resource.ResourceWithModifyPlan = &InstanceResource{}
+var _ resource.ResourceWithValidateConfig = &InstanceResource{}
+var _ resource.ResourceWithUpgradeState = &InstanceResource{}
+
+// 默认超时时间
+const (
+	instanceCreateTimeout = 10 * time.Minute
+	instanceUpdateTimeout = 10 * time.Minute
+	instanceDeleteTimeout = 20 * time.Minute
+)
 
 // InstanceResource 定义虚拟机资源实现
 type InstanceResource struct {
@@ -47,6 +67,9 @@ type InstanceResourceModel struct {
 	Password            types.String `tfsdk:"password"`
 	BlockDeviceMappings types.List   `tfsdk:"block_device_mappings"`
 
+	// 已废弃参数，仅用于给出迁移提示
+	SystemDiskSize types.Int64 `tfsdk:"system_disk_size"`
+
 	// 可选参数
 	MinCount         types.Int64  `tfsdk:"min_count"`
 	InstanceName     types.String `tfsdk:"instance_name"`
@@ -54,13 +77,23 @@ type InstanceResourceModel struct {
 	KeyName          types.String `tfsdk:"key_name"`
 	UserData         types.String `tfsdk:"user_data"`
 	Tags             types.Map    `tfsdk:"tags"`
+	InstanceState    types.String `tfsdk:"instance_state"`
+	PasswordVersion  types.Int64  `tfsdk:"password_version"`
+
+	// 保护设置
+	DisableApiTermination types.Bool `tfsdk:"disable_api_termination"`
+	DisableApiStop        types.Bool `tfsdk:"disable_api_stop"`
 
 	// 计算属性
 	ID               types.String `tfsdk:"id"`
+	TagsAll          types.Map    `tfsdk:"tags_all"`
 	PrivateIP        types.String `tfsdk:"private_ip"`
 	PublicIP         types.String `tfsdk:"public_ip"`
 	State            types.String `tfsdk:"state"`
 	AvailabilityZone types.String `tfsdk:"availability_zone"`
+
+	// 超时配置
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
 }
 
 // NewInstanceResource 创建新的虚拟机资源实例
@@ -95,6 +128,7 @@ func (r *InstanceResource) Configure(ctx context.Context, req resource.Configure
 func (r *InstanceResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "管理 BingoCloud 虚拟机实例",
+		Version:             1,
 
 		Attributes: map[string]schema.Attribute{
 			// 必需参数
@@ -117,13 +151,14 @@ func (r *InstanceResource) Schema(ctx context.Context, req resource.SchemaReques
 				},
 			},
 			"password": schema.StringAttribute{
-				MarkdownDescription: "实例登录密码",
+				MarkdownDescription: "实例登录密码，只写属性，不会保存到计划和状态中（需要 Terraform 1.11 及以上版本）。修改密码后需同时修改 `password_version` 才会生效",
 				Required:            true,
 				Sensitive:           true,
+				WriteOnly:           true,
 			},
 			"block_device_mappings": schema.ListNestedAttribute{
-				MarkdownDescription: "块设备映射配置列表，第一个元素为系统盘，后续为数据盘",
-				Required:            true,
+				MarkdownDescription: "块设备映射配置列表（必需），第一个元素为系统盘，后续为数据盘。修改系统盘会替换实例；数据盘的新增、删除、扩容和类型修改原地完成，数据盘不支持缩容。建议为数据盘指定 `device_name`，否则按顺序匹配",
+				Optional:            true,
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
 						"volume_size": schema.Int64Attribute{
@@ -141,21 +176,39 @@ func (r *InstanceResource) Schema(ctx context.Context, req resource.SchemaReques
 						},
 					},
 				},
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
 				PlanModifiers: []planmodifier.List{
-					listplanmodifier.RequiresReplace(),
+					listplanmodifier.RequiresReplaceIf(
+						blockDeviceMappingsRequiresReplace,
+						"系统盘变更时需要替换实例",
+						"系统盘变更时需要替换实例",
+					),
 				},
 			},
+			"system_disk_size": schema.Int64Attribute{
+				MarkdownDescription: "已废弃，请改用 `block_device_mappings` 的第一个元素配置系统盘",
+				DeprecationMessage:  "system_disk_size 已移除，请改用 block_device_mappings 的第一个元素配置系统盘",
+				Optional:            true,
+			},
 
 			// 可选参数
 			"min_count": schema.Int64Attribute{
-				MarkdownDescription: "创建的实例个数，默认为 1",
+				MarkdownDescription: "创建的实例个数，只能为 1。批量创建请使用 `bingocloud_instance_group`",
 				Optional:            true,
 				Computed:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 1),
+				},
 			},
 			"instance_name": schema.StringAttribute{
-				MarkdownDescription: "实例名称",
+				MarkdownDescription: "实例名称，即实例的 Name 标签，修改后原地生效。`tags` 中不能再配置 Name",
 				Optional:            true,
 				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"security_group_ids": schema.ListAttribute{
 				MarkdownDescription: "安全组 ID 列表",
@@ -167,8 +220,11 @@ func (r *InstanceResource) Schema(ctx context.Context, req resource.SchemaReques
 				},
 			},
 			"key_name": schema.StringAttribute{
-				MarkdownDescription: "SSH 密钥对名称",
+				MarkdownDescription: "SSH 密钥对名称，可以引用 `bingocloud_key_pair` 的 `key_name`，修改时重建实例",
 				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"user_data": schema.StringAttribute{
 				MarkdownDescription: "用户数据脚本（Base64 编码）",
@@ -177,10 +233,33 @@ func (r *InstanceResource) Schema(ctx context.Context, req resource.SchemaReques
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
-			"tags": schema.MapAttribute{
-				MarkdownDescription: "资源标签",
-				ElementType:         types.StringType,
+			"tags": tftags.TagsAttribute(),
+			"instance_state": schema.StringAttribute{
+				MarkdownDescription: "期望的电源状态（running 或 stopped），默认 running。实际状态见 `state`",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(ec2.InstanceStateNameRunning, ec2.InstanceStateNameStopped),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"password_version": schema.Int64Attribute{
+				MarkdownDescription: "密码版本号，修改后会通过密码重置接口原地重置实例的登录密码，不会替换实例",
+				Optional:            true,
+			},
+			"disable_api_termination": schema.BoolAttribute{
+				MarkdownDescription: "是否开启终止保护，默认 false。开启后无法删除实例，需先关闭并 apply",
 				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"disable_api_stop": schema.BoolAttribute{
+				MarkdownDescription: "是否开启停止保护，默认 false。开启后无法停止实例，也无法修改实例类型",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
 			},
 
 			// 计算属性（只读）
@@ -191,6 +270,7 @@ func (r *InstanceResource) Schema(ctx context.Context, req resource.SchemaReques
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"tags_all": tftags.TagsAllAttribute(),
 			"private_ip": schema.StringAttribute{
 				MarkdownDescription: "私有 IP 地址",
 				Computed:            true,
@@ -199,7 +279,7 @@ func (r *InstanceResource) Schema(ctx context.Context, req resource.SchemaReques
 				},
 			},
 			"public_ip": schema.StringAttribute{
-				MarkdownDescription: "公网 IP 地址",
+				MarkdownDescription: "平台自动分配的公网 IP 地址，绑定弹性 IP 后不会更新为弹性 IP 地址，弹性 IP 见 `bingocloud_eip` 的 `public_ip`",
 				Computed:            true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
@@ -220,6 +300,14 @@ func (r *InstanceResource) Schema(ctx context.Context, req resource.SchemaReques
 				},
 			},
 		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
+		},
 	}
 }
 
@@ -233,69 +321,57 @@ func (r *InstanceResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
-	// 构建 RunInstances 请求
-	instanceCount := int64(1) // 默认创建 1 个实例
-	if !plan.MinCount.IsNull() {
-		instanceCount = plan.MinCount.ValueInt64()
-	} else {
-		plan.MinCount = types.Int64Value(instanceCount)
+	createTimeout, diags := plan.Timeouts.Create(ctx, instanceCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	// 密码为只写属性，只能从配置中读取
+	var password types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("password"), &password)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
+	// 构建 RunInstances 请求，批量创建请使用 bingocloud_instance_group
+	plan.MinCount = types.Int64Value(1)
+
 	runInput := &ec2.RunInstancesInput{
 		ImageId:      aws.String(plan.ImageId.ValueString()),
 		InstanceType: aws.String(plan.InstanceType.ValueString()),
 		SubnetId:     aws.String(plan.SubnetID.ValueString()),
-		MinCount:     aws.Int64(instanceCount),
-		MaxCount:     aws.Int64(instanceCount),
-		InstanceName: aws.String(plan.InstanceName.ValueString()),
-		Password:     aws.String(plan.Password.ValueString()),
-	}
+		MinCount:     aws.Int64(1),
+		MaxCount:     aws.Int64(1),
+		Password:     aws.String(password.ValueString()),
 
-	// TODO: Password 参数暂时无法设置
-	// BingoCloud SDK 的 RunInstancesInput 结构体中没有 Password 字段
-	// 需要等待 SDK 更新或找到其他方式传递密码参数
-
-	// 配置块设备映射
-	var blockDeviceMappings []*ec2.BlockDeviceMapping
-	var bdmList []BlockDeviceMappingModel
-	resp.Diagnostics.Append(plan.BlockDeviceMappings.ElementsAs(ctx, &bdmList, false)...)
-	if resp.Diagnostics.HasError() {
-		return
+		DisableApiTermination: aws.Bool(plan.DisableApiTermination.ValueBool()),
 	}
 
-	// 处理每个块设备映射
-	for i, bdm := range bdmList {
-		deviceName := bdm.DeviceName.ValueString()
-
-		// 第一个元素（系统盘）如果没有指定 device_name，使用默认值
-		if i == 0 && deviceName == "" {
-			deviceName = "/dev/vda"
-			bdmList[i].DeviceName = types.StringValue(deviceName)
-		}
+	// 期望状态为 stopped 时，停止保护需在实例停止之后再开启
+	if plan.InstanceState.IsNull() || plan.InstanceState.IsUnknown() {
+		plan.InstanceState = types.StringValue(ec2.InstanceStateNameRunning)
+	}
+	stopAfterCreate := plan.InstanceState.ValueString() == ec2.InstanceStateNameStopped
+	if !stopAfterCreate {
+		runInput.DisableApiStop = aws.Bool(plan.DisableApiStop.ValueBool())
+	}
 
-		blockDeviceMappings = append(blockDeviceMappings, &ec2.BlockDeviceMapping{
-			DeviceName: aws.String(deviceName),
-			Ebs: &ec2.EbsBlockDevice{
-				VolumeSize:          aws.Int64(bdm.VolumeSize.ValueInt64()),
-				VolumeType:          aws.String(bdm.VolumeType.ValueString()),
-				DeleteOnTermination: aws.Bool(true),
-			},
-		})
+	// 实例名称同时作为 RunInstances 参数和 Name 标签传递
+	if !plan.InstanceName.IsNull() && !plan.InstanceName.IsUnknown() {
+		runInput.InstanceName = aws.String(plan.InstanceName.ValueString())
 	}
 
-	// 更新 plan 中的 BlockDeviceMappings（包含默认值）
-	updatedBdmList, diags := types.ListValueFrom(ctx, types.ObjectType{
-		AttrTypes: map[string]attr.Type{
-			"volume_size": types.Int64Type,
-			"volume_type": types.StringType,
-			"device_name": types.StringType,
-		},
-	}, bdmList)
+	// 配置块设备映射，并更新 plan 中的 BlockDeviceMappings（包含默认值）
+	blockDeviceMappings, bdmList, diags := expandBlockDeviceMappings(ctx, plan.BlockDeviceMappings)
 	resp.Diagnostics.Append(diags...)
-	if !resp.Diagnostics.HasError() {
-		plan.BlockDeviceMappings = updatedBdmList
+	if resp.Diagnostics.HasError() {
+		return
 	}
-
+	plan.BlockDeviceMappings = bdmList
 	runInput.BlockDeviceMappings = blockDeviceMappings
 
 	// 配置安全组
@@ -319,34 +395,12 @@ func (r *InstanceResource) Create(ctx context.Context, req resource.CreateReques
 	}
 
 	// 配置标签
-	tags := []*ec2.Tag{}
-	if !plan.InstanceName.IsNull() {
-		tags = append(tags, &ec2.Tag{
-			Key:   aws.String("Name"),
-			Value: aws.String(plan.InstanceName.ValueString()),
-		})
-	}
-	if !plan.Tags.IsNull() {
-		var tagMap map[string]string
-		resp.Diagnostics.Append(plan.Tags.ElementsAs(ctx, &tagMap, false)...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
-		for k, v := range tagMap {
-			tags = append(tags, &ec2.Tag{
-				Key:   aws.String(k),
-				Value: aws.String(v),
-			})
-		}
-	}
-	if len(tags) > 0 {
-		runInput.TagSpecifications = []*ec2.TagSpecification{
-			{
-				ResourceType: aws.String("instance"),
-				Tags:         tags,
-			},
-		}
+	tagSpecifications, diags := expandInstanceTagSpecifications(ctx, plan.InstanceName, plan.TagsAll)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	runInput.TagSpecifications = tagSpecifications
 
 	// 调用 API 创建实例
 	tflog.Debug(ctx, "创建 BingoCloud 实例", map[string]interface{}{
@@ -374,6 +428,14 @@ func (r *InstanceResource) Create(ctx context.Context, req resource.CreateReques
 	instance := result.Instances[0]
 	plan.ID = types.StringValue(aws.StringValue(instance.InstanceId))
 
+	// 立即保存实例 ID：后续步骤失败时 Terraform 会将资源标记为 tainted，
+	// 避免实例游离在状态之外
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), plan.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("timeouts"), plan.Timeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// 等待实例运行
 	tflog.Debug(ctx, "等待实例运行", map[string]interface{}{
 		"instance_id": plan.ID.ValueString(),
@@ -381,55 +443,58 @@ func (r *InstanceResource) Create(ctx context.Context, req resource.CreateReques
 
 	err = r.client.EC2Client().WaitUntilInstanceRunningWithContext(ctx, &ec2.DescribeInstancesInput{
 		InstanceIds: []*string{instance.InstanceId},
-	})
+	}, waiterOptions(createTimeout)...)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"等待实例运行失败",
-			"实例创建成功但未能进入运行状态: "+err.Error(),
+			"实例创建成功但未能进入运行状态: "+waitErrorDetail(ctx, "创建", createTimeout, err, instanceLastState(r.client.EC2Client(), plan.ID.ValueString())),
 		)
+		r.handleCreateFailure(ctx, &plan, resp)
 		return
 	}
 
+	// 期望状态为 stopped 时，实例运行后再停止
+	if stopAfterCreate {
+		if err := stopInstance(ctx, r.client.EC2Client(), plan.ID.ValueString(), createTimeout); err != nil {
+			resp.Diagnostics.AddError(
+				"停止实例失败",
+				"实例创建成功但未能进入停止状态: "+waitErrorDetail(ctx, "创建", createTimeout, err, instanceLastState(r.client.EC2Client(), plan.ID.ValueString())),
+			)
+			r.handleCreateFailure(ctx, &plan, resp)
+			return
+		}
+
+		if plan.DisableApiStop.ValueBool() {
+			if err := modifyInstanceProtection(ctx, r.client.EC2Client(), plan.ID.ValueString(), ec2.InstanceAttributeNameDisableApiStop, true); err != nil {
+				resp.Diagnostics.AddError(
+					"开启停止保护失败",
+					"实例创建成功但无法开启停止保护: "+err.Error(),
+				)
+				r.handleCreateFailure(ctx, &plan, resp)
+				return
+			}
+		}
+	}
+
 	// 读取实例详细信息以填充计算属性
-	describeResult, err := r.client.EC2Client().DescribeInstancesWithContext(ctx, &ec2.DescribeInstancesInput{
-		InstanceIds: []*string{instance.InstanceId},
-	})
+	inst, err := findInstanceByID(ctx, r.client.EC2Client(), plan.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"读取实例详情失败",
 			"实例创建成功但无法读取详细信息: "+err.Error(),
 		)
+		r.handleCreateFailure(ctx, &plan, resp)
 		return
 	}
 
-	if len(describeResult.Reservations) > 0 && len(describeResult.Reservations[0].Instances) > 0 {
-		inst := describeResult.Reservations[0].Instances[0]
-
-		// 填充计算属性
-		plan.State = types.StringValue(aws.StringValue(inst.State.Name))
-		plan.AvailabilityZone = types.StringValue(aws.StringValue(inst.Placement.AvailabilityZone))
-
-		if inst.PrivateIpAddress != nil {
-			plan.PrivateIP = types.StringValue(aws.StringValue(inst.PrivateIpAddress))
-		}
-		if inst.PublicIpAddress != nil {
-			plan.PublicIP = types.StringValue(aws.StringValue(inst.PublicIpAddress))
-		}
+	// 未配置实例名称时以平台生成的 Name 标签为准
+	if plan.InstanceName.IsUnknown() {
+		plan.InstanceName = tftags.NameValue(tagsToMap(inst.Tags))
+	}
 
-		// 如果用户没有提供 security_group_ids，从 API 读取并填充
-		if plan.SecurityGroupIDs.IsNull() || plan.SecurityGroupIDs.IsUnknown() {
-			sgIDs := make([]string, 0, len(inst.SecurityGroups))
-			for _, sg := range inst.SecurityGroups {
-				if sg.GroupId != nil {
-					sgIDs = append(sgIDs, aws.StringValue(sg.GroupId))
-				}
-			}
-			sgList, diags := types.ListValueFrom(ctx, types.StringType, sgIDs)
-			resp.Diagnostics.Append(diags...)
-			if !resp.Diagnostics.HasError() {
-				plan.SecurityGroupIDs = sgList
-			}
-		}
+	resp.Diagnostics.Append(flattenInstanceComputed(ctx, inst, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
 	// 读取最新状态并保存
@@ -437,6 +502,50 @@ func (r *InstanceResource) Create(ctx context.Context, req resource.CreateReques
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
+// handleCreateFailure 处理 RunInstances 成功之后的创建失败
+// 默认保留状态中的实例 ID，由 Terraform 标记为 tainted 并在下次 apply 时替换；
+// 若 provider 启用了 terminate_on_create_failure，则终止创建了一半的实例并从状态中移除
+func (r *InstanceResource) handleCreateFailure(ctx context.Context, plan *InstanceResourceModel, resp *resource.CreateResponse) {
+	id := plan.ID.ValueString()
+
+	if !r.client.TerminateOnCreateFailure {
+		resp.Diagnostics.AddWarning(
+			"实例已标记为 tainted",
+			"实例 "+id+" 已创建但未能完成初始化，已保存到状态中，下次 apply 时将被替换",
+		)
+		return
+	}
+
+	// 原 context 可能已超时，使用独立的 context 进行清理
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), instanceDeleteTimeout)
+	defer cancel()
+
+	tflog.Debug(ctx, "终止创建失败的实例", map[string]interface{}{
+		"instance_id": id,
+	})
+
+	// 创建时开启了终止保护，需先关闭才能终止
+	if plan.DisableApiTermination.ValueBool() {
+		if err := modifyInstanceProtection(ctx, r.client.EC2Client(), id, ec2.InstanceAttributeNameDisableApiTermination, false); err != nil {
+			resp.Diagnostics.AddError(
+				"回滚实例失败",
+				"无法关闭创建失败的实例 "+id+" 的终止保护，实例已保存到状态中并将被标记为 tainted: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	if err := terminateInstances(ctx, r.client.EC2Client(), []string{id}, instanceDeleteTimeout); err != nil {
+		resp.Diagnostics.AddError(
+			"回滚实例失败",
+			"无法终止创建失败的实例 "+id+"，实例已保存到状态中并将被标记为 tainted: "+err.Error(),
+		)
+		return
+	}
+
+	resp.State.RemoveResource(ctx)
+}
+
 // Read 读取虚拟机实例状态
 func (r *InstanceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var state InstanceResourceModel
@@ -448,11 +557,7 @@ func (r *InstanceResource) Read(ctx context.Context, req resource.ReadRequest, r
 	}
 
 	// 调用 DescribeInstances API
-	input := &ec2.DescribeInstancesInput{
-		InstanceIds: []*string{aws.String(state.ID.ValueString())},
-	}
-
-	result, err := r.client.EC2Client().DescribeInstancesWithContext(ctx, input)
+	instance, err := findInstanceByID(ctx, r.client.EC2Client(), state.ID.ValueString())
 	if err != nil {
 		if isNotFoundError(err) {
 			// 实例不存在，从状态中移除
@@ -466,27 +571,39 @@ func (r *InstanceResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
-	if len(result.Reservations) == 0 || len(result.Reservations[0].Instances) == 0 {
-		// 实例不存在，从状态中移除
-		resp.State.RemoveResource(ctx)
-		return
-	}
-
-	instance := result.Reservations[0].Instances[0]
-
 	// 更新模型 - 基本属性
 	state.ImageId = types.StringValue(aws.StringValue(instance.ImageId))
 	state.InstanceType = types.StringValue(aws.StringValue(instance.InstanceType))
 	state.SubnetID = types.StringValue(aws.StringValue(instance.SubnetId))
-	state.State = types.StringValue(aws.StringValue(instance.State.Name))
-	state.AvailabilityZone = types.StringValue(aws.StringValue(instance.Placement.AvailabilityZone))
 
-	// IP 地址
-	if instance.PrivateIpAddress != nil {
-		state.PrivateIP = types.StringValue(aws.StringValue(instance.PrivateIpAddress))
+	// 计算属性（状态、可用区、IP 地址）
+	priorPublicIP := state.PublicIP
+	resp.Diagnostics.Append(flattenInstanceComputed(ctx, instance, &state)...)
+	state.PublicIP = flattenInstancePublicIP(ctx, r.client.EC2Client(), instance, priorPublicIP)
+
+	// 期望电源状态：只在实例处于稳定状态时同步，过渡状态保持原值
+	switch apiState := aws.StringValue(instance.State.Name); apiState {
+	case ec2.InstanceStateNameRunning, ec2.InstanceStateNameStopped:
+		state.InstanceState = types.StringValue(apiState)
 	}
-	if instance.PublicIpAddress != nil {
-		state.PublicIP = types.StringValue(aws.StringValue(instance.PublicIpAddress))
+
+	// 终止保护和停止保护
+	for _, p := range []struct {
+		attribute string
+		value     *types.Bool
+	}{
+		{ec2.InstanceAttributeNameDisableApiTermination, &state.DisableApiTermination},
+		{ec2.InstanceAttributeNameDisableApiStop, &state.DisableApiStop},
+	} {
+		enabled, err := findInstanceProtection(ctx, r.client.EC2Client(), state.ID.ValueString(), p.attribute)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"读取实例保护设置失败",
+				"无法读取实例 "+state.ID.ValueString()+" 的 "+p.attribute+" 属性: "+err.Error(),
+			)
+			return
+		}
+		*p.value = types.BoolValue(enabled)
 	}
 
 	// 密钥对 - 只在实例有密钥对时才设置
@@ -507,69 +624,445 @@ func (r *InstanceResource) Read(ctx context.Context, req resource.ReadRequest, r
 		state.SecurityGroupIDs = sgList
 	}
 
-	// 标签
-	tagMap := make(map[string]string)
-	for _, tag := range instance.Tags {
-		if tag.Key != nil && tag.Value != nil {
-			key := aws.StringValue(tag.Key)
-			if key == "Name" {
-				state.InstanceName = types.StringValue(aws.StringValue(tag.Value))
-			} else {
-				tagMap[key] = aws.StringValue(tag.Value)
-			}
-		}
-	}
-	// 始终设置 tags，即使为空
-	tagsValue, diags := types.MapValueFrom(ctx, types.StringType, tagMap)
+	// 标签：Name 标签对应 instance_name，其余标签按 provider 的默认标签和忽略标签配置拆分到 tags 和 tags_all
+	tagMap := tagsToMap(instance.Tags)
+	state.InstanceName = tftags.NameValue(tagMap)
+	tagsValue, tagsAllValue, diags := tftags.Flatten(ctx, tagMap, state.Tags, r.client.DefaultTagsConfig, r.client.IgnoreTagsConfig, tftags.NameKey)
 	resp.Diagnostics.Append(diags...)
 	if !resp.Diagnostics.HasError() {
 		state.Tags = tagsValue
+		state.TagsAll = tagsAllValue
 	}
 
-	// 注意：system_disk_size 在导入时无法从 API 读取，保持状态中的值不变
+	// 块设备映射：根据实例挂载的卷查询大小和类型
+	volumeIDs := make([]string, 0, len(instance.BlockDeviceMappings))
+	for _, bdm := range instance.BlockDeviceMappings {
+		if bdm.Ebs != nil && bdm.Ebs.VolumeId != nil {
+			volumeIDs = append(volumeIDs, aws.StringValue(bdm.Ebs.VolumeId))
+		}
+	}
+	volumes, err := findVolumesByIDs(ctx, r.client.EC2Client(), volumeIDs)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"读取实例磁盘失败",
+			"无法读取实例 "+state.ID.ValueString()+" 的磁盘: "+err.Error(),
+		)
+		return
+	}
+	bdmList, diags := flattenBlockDeviceMappings(ctx, instance, volumes, state.BlockDeviceMappings)
+	resp.Diagnostics.Append(diags...)
+	if !resp.Diagnostics.HasError() {
+		state.BlockDeviceMappings = bdmList
+	}
 
 	// 保存更新后的状态
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
-// Update 更新虚拟机实例（暂不支持）
+// Update 更新虚拟机实例，只对发生变化的属性调用对应的 API
 func (r *InstanceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var plan InstanceResourceModel
+	var plan, state InstanceResourceModel
 
-	// 读取计划数据
+	// 读取计划数据和当前状态
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// 暂时直接保存状态，后续可以实现具体的更新逻辑
-	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
-}
+	updateTimeout, diags := plan.Timeouts.Update(ctx, instanceUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-// Delete 删除虚拟机实例
-func (r *InstanceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var state InstanceResourceModel
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
 
-	// 读取 Terraform 状态数据
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	conn := r.client.EC2Client()
+	instanceID := state.ID.ValueString()
+
+	// 关闭保护放在最前，避免停止保护阻止下面的启停操作
+	resp.Diagnostics.Append(updateInstanceProtection(ctx, conn, instanceID, plan, state, false)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// 调用 TerminateInstances API
-	_, err := r.client.EC2Client().TerminateInstancesWithContext(ctx, &ec2.TerminateInstancesInput{
-		InstanceIds: []*string{aws.String(state.ID.ValueString())},
-	})
+	// 实例类型：需要停机后修改，再重新启动
+	if !plan.InstanceType.Equal(state.InstanceType) {
+		tflog.Debug(ctx, "修改实例类型", map[string]interface{}{
+			"instance_id":   instanceID,
+			"instance_type": plan.InstanceType.ValueString(),
+		})
+
+		start := plan.InstanceState.ValueString() != ec2.InstanceStateNameStopped
+		if err := modifyInstanceType(ctx, conn, instanceID, plan.InstanceType.ValueString(), start, updateTimeout); err != nil {
+			resp.Diagnostics.AddError(
+				"修改实例类型失败",
+				"无法修改实例 "+instanceID+" 的类型: "+waitErrorDetail(ctx, "更新", updateTimeout, err, instanceLastState(conn, instanceID)),
+			)
+			return
+		}
+	}
+
+	// 电源状态：实例类型变更时已按期望状态处理
+	if plan.InstanceType.Equal(state.InstanceType) && !plan.InstanceState.IsUnknown() && !plan.InstanceState.Equal(state.InstanceState) {
+		tflog.Debug(ctx, "调整实例电源状态", map[string]interface{}{
+			"instance_id":    instanceID,
+			"instance_state": plan.InstanceState.ValueString(),
+		})
+
+		if err := reconcileInstanceState(ctx, conn, instanceID, plan.InstanceState.ValueString(), updateTimeout); err != nil {
+			resp.Diagnostics.AddError(
+				"调整实例电源状态失败",
+				"无法调整实例 "+instanceID+" 的电源状态: "+waitErrorDetail(ctx, "更新", updateTimeout, err, instanceLastState(conn, instanceID)),
+			)
+			return
+		}
+	}
+
+	// 登录密码：密码版本号变化时原地重置
+	if !plan.PasswordVersion.Equal(state.PasswordVersion) {
+		var password types.String
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("password"), &password)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		tflog.Debug(ctx, "重置实例密码", map[string]interface{}{
+			"instance_id":      instanceID,
+			"password_version": plan.PasswordVersion.ValueInt64(),
+		})
+
+		if err := resetInstancePassword(ctx, conn, instanceID, password.ValueString()); err != nil {
+			resp.Diagnostics.AddError(
+				"重置实例密码失败",
+				"无法重置实例 "+instanceID+" 的登录密码: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	// 标签：按包含默认标签的 tags_all 比较，删除移除的键，创建或覆盖新增和修改的键
+	if !plan.TagsAll.Equal(state.TagsAll) {
+		oldTags := make(map[string]string)
+		newTags := make(map[string]string)
+		if !state.TagsAll.IsNull() {
+			resp.Diagnostics.Append(state.TagsAll.ElementsAs(ctx, &oldTags, false)...)
+		}
+		if !plan.TagsAll.IsNull() && !plan.TagsAll.IsUnknown() {
+			resp.Diagnostics.Append(plan.TagsAll.ElementsAs(ctx, &newTags, false)...)
+		}
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if err := updateTags(ctx, conn, instanceID, oldTags, newTags); err != nil {
+			resp.Diagnostics.AddError(
+				"更新实例标签失败",
+				"无法更新实例 "+instanceID+" 的标签: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	// 实例名称：通过 Name 标签原地修改
+	if !plan.InstanceName.IsUnknown() && !plan.InstanceName.IsNull() && !plan.InstanceName.Equal(state.InstanceName) {
+		tflog.Debug(ctx, "修改实例名称", map[string]interface{}{
+			"instance_id":   instanceID,
+			"instance_name": plan.InstanceName.ValueString(),
+		})
+
+		if err := updateTags(ctx, conn, instanceID, nil, map[string]string{tftags.NameKey: plan.InstanceName.ValueString()}); err != nil {
+			resp.Diagnostics.AddError(
+				"修改实例名称失败",
+				"无法修改实例 "+instanceID+" 的名称: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	// 安全组：整体替换实例关联的安全组
+	if !plan.SecurityGroupIDs.IsUnknown() && !plan.SecurityGroupIDs.Equal(state.SecurityGroupIDs) {
+		var sgIDs []string
+		resp.Diagnostics.Append(plan.SecurityGroupIDs.ElementsAs(ctx, &sgIDs, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		tflog.Debug(ctx, "修改实例安全组", map[string]interface{}{
+			"instance_id":        instanceID,
+			"security_group_ids": sgIDs,
+		})
+
+		_, err := conn.ModifyInstanceAttributeWithContext(ctx, &ec2.ModifyInstanceAttributeInput{
+			InstanceId: aws.String(instanceID),
+			Groups:     aws.StringSlice(sgIDs),
+		})
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"修改实例安全组失败",
+				"无法修改实例 "+instanceID+" 的安全组: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	// 块设备映射：原地调整数据盘，并补全计划中未知的设备名称
+	if !plan.BlockDeviceMappings.Equal(state.BlockDeviceMappings) {
+		var stateList, planList []BlockDeviceMappingModel
+		resp.Diagnostics.Append(state.BlockDeviceMappings.ElementsAs(ctx, &stateList, false)...)
+		resp.Diagnostics.Append(plan.BlockDeviceMappings.ElementsAs(ctx, &planList, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		instance, err := findInstanceByID(ctx, conn, instanceID)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"读取实例失败",
+				"无法读取实例 "+instanceID+": "+err.Error(),
+			)
+			return
+		}
+
+		bdmList, err := updateDataDisks(ctx, conn, instance, stateList, planList, updateTimeout)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"更新实例数据盘失败",
+				"无法更新实例 "+instanceID+" 的数据盘: "+waitErrorDetail(ctx, "更新", updateTimeout, err, instanceLastState(conn, instanceID)),
+			)
+
+			// 保存已经完成的变更，避免已卸载、扩容或新增的数据盘以及之前已生效的修改从状态中丢失
+			bdmValue, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: blockDeviceMappingAttrTypes}, bdmList)
+			resp.Diagnostics.Append(diags...)
+			if !diags.HasError() {
+				partial := instancePartialState(plan, state, bdmValue)
+				resp.Diagnostics.Append(resp.State.Set(ctx, &partial)...)
+			}
+			return
+		}
+
+		bdmValue, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: blockDeviceMappingAttrTypes}, bdmList)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		plan.BlockDeviceMappings = bdmValue
+	}
+
+	// 开启保护放在最后，确保其它变更已经完成
+	resp.Diagnostics.Append(updateInstanceProtection(ctx, conn, instanceID, plan, state, true)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// 重新读取实例以刷新计算属性
+	instance, err := findInstanceByID(ctx, conn, instanceID)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"删除实例失败",
-			"无法删除实例 "+state.ID.ValueString()+": "+err.Error(),
+			"读取实例详情失败",
+			"实例更新成功但无法读取详细信息: "+err.Error(),
 		)
 		return
 	}
 
-	tflog.Trace(ctx, "删除实例成功")
-}
+	priorPublicIP := plan.PublicIP
+	resp.Diagnostics.Append(flattenInstanceComputed(ctx, instance, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.PublicIP = flattenInstancePublicIP(ctx, conn, instance, priorPublicIP)
+
+	tflog.Trace(ctx, "更新实例成功")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete 删除虚拟机实例
+func (r *InstanceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state InstanceResourceModel
+
+	// 读取 Terraform 状态数据
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, instanceDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	conn := r.client.EC2Client()
+	instanceID := state.ID.ValueString()
+
+	// 开启终止保护时拒绝删除
+	if state.DisableApiTermination.ValueBool() {
+		resp.Diagnostics.AddError(
+			"实例已开启终止保护",
+			"实例 "+instanceID+" 开启了终止保护，无法删除。如确需删除，请先将 disable_api_termination 设置为 false 并执行 apply",
+		)
+		return
+	}
+
+	// 调用 TerminateInstances API
+	_, err := conn.TerminateInstancesWithContext(ctx, &ec2.TerminateInstancesInput{
+		InstanceIds: []*string{aws.String(instanceID)},
+	})
+	if err != nil {
+		if isNotFoundError(err) {
+			// 实例已不存在，视为删除成功
+			return
+		}
+		if isOperationNotPermittedError(err) {
+			// 状态未刷新时，终止保护可能已在 Terraform 之外开启
+			resp.Diagnostics.AddError(
+				"实例已开启终止保护",
+				"实例 "+instanceID+" 开启了终止保护，无法删除。请先执行 terraform refresh，将 disable_api_termination 设置为 false 并执行 apply: "+err.Error(),
+			)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"删除实例失败",
+			"无法删除实例 "+instanceID+": "+err.Error(),
+		)
+		return
+	}
+
+	// 等待实例终止，避免同一次 apply 中删除子网、安全组等依赖资源时失败
+	tflog.Debug(ctx, "等待实例终止", map[string]interface{}{
+		"instance_id": instanceID,
+	})
+
+	if err := waitInstanceTerminated(ctx, conn, instanceID, deleteTimeout); err != nil {
+		resp.Diagnostics.AddError(
+			"等待实例终止失败",
+			"实例 "+instanceID+" 未能进入 terminated 状态: "+waitErrorDetail(ctx, "删除", deleteTimeout, err, instanceLastState(conn, instanceID)),
+		)
+		return
+	}
+
+	tflog.Trace(ctx, "删除实例成功")
+}
+
+// ValidateConfig 校验配置：tags 中不能配置 Name，对仍在使用 system_disk_size 的旧配置给出改写提示
+func (r *InstanceResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config InstanceResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Name 标签只能通过 instance_name 配置
+	resp.Diagnostics.Append(tftags.ValidateNameTag(ctx, config.Tags, "instance_name")...)
+
+	if !config.SystemDiskSize.IsNull() {
+		size := "<系统盘大小>"
+		if !config.SystemDiskSize.IsUnknown() {
+			size = fmt.Sprintf("%d", config.SystemDiskSize.ValueInt64())
+		}
+		resp.Diagnostics.AddAttributeError(
+			path.Root("system_disk_size"),
+			"system_disk_size 已移除",
+			"请删除 system_disk_size，改用 block_device_mappings 的第一个元素配置系统盘，例如:\n\n"+
+				"  block_device_mappings = [\n"+
+				"    {\n"+
+				"      volume_size = "+size+"\n"+
+				"      volume_type = \"<磁盘类型>\"\n"+
+				"    }\n"+
+				"  ]\n\n"+
+				"已有状态会在升级时自动迁移为 /dev/vda 上的系统盘，不会替换实例。",
+		)
+		return
+	}
+
+	if config.BlockDeviceMappings.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("block_device_mappings"),
+			"缺少 block_device_mappings 配置",
+			"必须通过 block_device_mappings 配置至少一个磁盘，第一个元素为系统盘",
+		)
+	}
+}
+
+// ModifyPlan 调整计划：计算 tags_all；变更实例类型或电源状态需要启停实例，公网 IP 可能重新分配；数据盘不支持缩容
+func (r *InstanceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// 销毁时无需调整
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	// 合并 provider 的默认标签，创建时同样需要
+	if r.client != nil {
+		resp.Diagnostics.Append(tftags.ModifyPlan(ctx, r.client.DefaultTagsConfig, r.client.IgnoreTagsConfig, &resp.Plan, tftags.NameKey)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// 创建时无需进一步调整
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	var plan, state InstanceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.InstanceType.IsUnknown() && !plan.InstanceType.Equal(state.InstanceType) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("public_ip"), types.StringUnknown())...)
+	}
+
+	// 启停实例后实际状态和公网 IP 都会变化
+	if !plan.InstanceState.Equal(state.InstanceState) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("state"), types.StringUnknown())...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("public_ip"), types.StringUnknown())...)
+	}
+
+	// 停止保护开启时无法停止实例
+	if plan.DisableApiStop.ValueBool() {
+		typeChanged := !plan.InstanceType.IsUnknown() && !plan.InstanceType.Equal(state.InstanceType) &&
+			state.InstanceState.ValueString() != ec2.InstanceStateNameStopped
+		stopping := plan.InstanceState.ValueString() == ec2.InstanceStateNameStopped && !plan.InstanceState.Equal(state.InstanceState)
+		if typeChanged || stopping {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("disable_api_stop"),
+				"实例已开启停止保护",
+				"修改实例类型或停止实例需要先停止实例，请在同一次变更中将 disable_api_stop 设置为 false",
+			)
+		}
+	}
+
+	// 数据盘不支持缩容
+	if !plan.BlockDeviceMappings.IsUnknown() && !plan.BlockDeviceMappings.Equal(state.BlockDeviceMappings) {
+		var stateList, planList []BlockDeviceMappingModel
+		resp.Diagnostics.Append(state.BlockDeviceMappings.ElementsAs(ctx, &stateList, false)...)
+		resp.Diagnostics.Append(plan.BlockDeviceMappings.ElementsAs(ctx, &planList, false)...)
+		if resp.Diagnostics.HasError() || len(stateList) < 2 || len(planList) < 2 {
+			return
+		}
+
+		matches, _ := matchDataDisks(stateList[1:], planList[1:])
+		for pi, si := range matches {
+			p, s := planList[pi+1], stateList[si+1]
+			if p.VolumeSize.IsUnknown() || p.VolumeSize.ValueInt64() >= s.VolumeSize.ValueInt64() {
+				continue
+			}
+			resp.Diagnostics.AddAttributeError(
+				path.Root("block_device_mappings").AtListIndex(pi+1).AtName("volume_size"),
+				"不支持缩小数据盘",
+				fmt.Sprintf("数据盘 %s 当前大小为 %d GB，不能缩小到 %d GB", s.DeviceName.ValueString(), s.VolumeSize.ValueInt64(), p.VolumeSize.ValueInt64()),
+			)
+		}
+	}
+}
 
 // ImportState 支持通过实例 ID 导入资源
 func (r *InstanceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
@@ -583,3 +1076,524 @@ func isNotFoundError(err error) bool {
 	}
 	return false
 }
+
+// isOperationNotPermittedError 判断是否为保护设置导致的操作被拒绝错误
+func isOperationNotPermittedError(err error) bool {
+	if awsErr, ok := err.(awserr.Error); ok {
+		return awsErr.Code() == "OperationNotPermitted"
+	}
+	return false
+}
+
+// blockDeviceMappingAttrTypes 块设备映射嵌套对象的属性类型
+var blockDeviceMappingAttrTypes = map[string]attr.Type{
+	"volume_size": types.Int64Type,
+	"volume_type": types.StringType,
+	"device_name": types.StringType,
+}
+
+// expandBlockDeviceMappings 将块设备映射列表转换为 API 参数
+// 第一个元素（系统盘）未指定 device_name 时使用 /dev/vda，返回补全默认值后的列表
+func expandBlockDeviceMappings(ctx context.Context, list types.List) ([]*ec2.BlockDeviceMapping, types.List, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	var bdmList []BlockDeviceMappingModel
+	diags.Append(list.ElementsAs(ctx, &bdmList, false)...)
+	if diags.HasError() {
+		return nil, list, diags
+	}
+
+	blockDeviceMappings := make([]*ec2.BlockDeviceMapping, 0, len(bdmList))
+	for i, bdm := range bdmList {
+		deviceName := bdm.DeviceName.ValueString()
+
+		// 第一个元素（系统盘）如果没有指定 device_name，使用默认值
+		if i == 0 && deviceName == "" {
+			deviceName = "/dev/vda"
+			bdmList[i].DeviceName = types.StringValue(deviceName)
+		}
+		// 数据盘未指定 device_name 时由平台分配
+		if bdmList[i].DeviceName.IsUnknown() {
+			bdmList[i].DeviceName = types.StringNull()
+		}
+
+		blockDeviceMappings = append(blockDeviceMappings, &ec2.BlockDeviceMapping{
+			DeviceName: aws.String(deviceName),
+			Ebs: &ec2.EbsBlockDevice{
+				VolumeSize:          aws.Int64(bdm.VolumeSize.ValueInt64()),
+				VolumeType:          aws.String(bdm.VolumeType.ValueString()),
+				DeleteOnTermination: aws.Bool(true),
+			},
+		})
+	}
+
+	updated, d := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: blockDeviceMappingAttrTypes}, bdmList)
+	diags.Append(d...)
+	if diags.HasError() {
+		return nil, list, diags
+	}
+
+	return blockDeviceMappings, updated, diags
+}
+
+// expandInstanceTagSpecifications 构建实例的标签规格，实例名称作为 Name 标签，tags 中不包含 Name
+func expandInstanceTagSpecifications(ctx context.Context, name types.String, tags types.Map) ([]*ec2.TagSpecification, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	ec2Tags := []*ec2.Tag{}
+	if !name.IsNull() && !name.IsUnknown() {
+		ec2Tags = append(ec2Tags, &ec2.Tag{
+			Key:   aws.String(tftags.NameKey),
+			Value: aws.String(name.ValueString()),
+		})
+	}
+	if !tags.IsNull() && !tags.IsUnknown() {
+		var tagMap map[string]string
+		diags.Append(tags.ElementsAs(ctx, &tagMap, false)...)
+		if diags.HasError() {
+			return nil, diags
+		}
+		for k, v := range tagMap {
+			ec2Tags = append(ec2Tags, &ec2.Tag{
+				Key:   aws.String(k),
+				Value: aws.String(v),
+			})
+		}
+	}
+	if len(ec2Tags) == 0 {
+		return nil, diags
+	}
+
+	return []*ec2.TagSpecification{
+		{
+			ResourceType: aws.String("instance"),
+			Tags:         ec2Tags,
+		},
+	}, diags
+}
+
+// blockDeviceMappingsRequiresReplace 判断块设备映射的变更是否需要替换实例
+// 只有系统盘（第一个元素）的变更需要替换，数据盘的增删和扩容在 Update 中原地完成；
+// 计划中未知的设备名称、从旧状态迁移而缺失的磁盘类型不视为变更
+func blockDeviceMappingsRequiresReplace(ctx context.Context, req planmodifier.ListRequest, resp *listplanmodifier.RequiresReplaceIfFuncResponse) {
+	var stateList, planList []BlockDeviceMappingModel
+	resp.Diagnostics.Append(req.StateValue.ElementsAs(ctx, &stateList, false)...)
+	resp.Diagnostics.Append(req.PlanValue.ElementsAs(ctx, &planList, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if len(stateList) == 0 || len(planList) == 0 {
+		resp.RequiresReplace = len(stateList) != len(planList)
+		return
+	}
+
+	resp.RequiresReplace = !blockDeviceMappingEquivalent(stateList[0], planList[0])
+}
+
+// blockDeviceMappingEquivalent 判断状态中的磁盘与计划中的磁盘是否一致
+func blockDeviceMappingEquivalent(state, plan BlockDeviceMappingModel) bool {
+	if !plan.VolumeSize.Equal(state.VolumeSize) {
+		return false
+	}
+	if !state.VolumeType.IsNull() && !plan.VolumeType.Equal(state.VolumeType) {
+		return false
+	}
+	if !plan.DeviceName.IsUnknown() && !state.DeviceName.IsNull() && !plan.DeviceName.Equal(state.DeviceName) {
+		return false
+	}
+	return true
+}
+
+// findInstanceByID 根据实例 ID 查询实例，实例不存在时返回 InvalidInstanceID.NotFound 错误
+func findInstanceByID(ctx context.Context, conn *ec2.EC2, id string) (*ec2.Instance, error) {
+	result, err := conn.DescribeInstancesWithContext(ctx, &ec2.DescribeInstancesInput{
+		InstanceIds: []*string{aws.String(id)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Reservations) == 0 || len(result.Reservations[0].Instances) == 0 {
+		return nil, awserr.New("InvalidInstanceID.NotFound", "实例 "+id+" 不存在", nil)
+	}
+
+	return result.Reservations[0].Instances[0], nil
+}
+
+// findVolumesByIDs 批量查询卷，返回以卷 ID 为键的映射
+func findVolumesByIDs(ctx context.Context, conn *ec2.EC2, ids []string) (map[string]*ec2.Volume, error) {
+	volumes := make(map[string]*ec2.Volume, len(ids))
+	if len(ids) == 0 {
+		return volumes, nil
+	}
+
+	input := &ec2.DescribeVolumesInput{
+		VolumeIds: aws.StringSlice(ids),
+	}
+
+	err := conn.DescribeVolumesPagesWithContext(ctx, input, func(page *ec2.DescribeVolumesOutput, lastPage bool) bool {
+		for _, volume := range page.Volumes {
+			volumes[aws.StringValue(volume.VolumeId)] = volume
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return volumes, nil
+}
+
+// findInstancesByIDs 批量查询实例，返回以实例 ID 为键的映射
+// 使用 instance-id 过滤器查询，部分实例不存在时不会报错；已终止的实例视为不存在
+func findInstancesByIDs(ctx context.Context, conn *ec2.EC2, ids []string) (map[string]*ec2.Instance, error) {
+	instances := make(map[string]*ec2.Instance, len(ids))
+	if len(ids) == 0 {
+		return instances, nil
+	}
+
+	input := &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("instance-id"),
+				Values: aws.StringSlice(ids),
+			},
+		},
+	}
+
+	err := conn.DescribeInstancesPagesWithContext(ctx, input, func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				if instance.State != nil {
+					switch aws.StringValue(instance.State.Name) {
+					case ec2.InstanceStateNameTerminated, ec2.InstanceStateNameShuttingDown:
+						continue
+					}
+				}
+				instances[aws.StringValue(instance.InstanceId)] = instance
+			}
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return instances, nil
+}
+
+// flattenInstanceComputed 将 API 返回的实例信息写入模型中的计算属性
+func flattenInstanceComputed(ctx context.Context, instance *ec2.Instance, model *InstanceResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	model.State = types.StringValue(aws.StringValue(instance.State.Name))
+	model.AvailabilityZone = types.StringValue(aws.StringValue(instance.Placement.AvailabilityZone))
+	model.PrivateIP = types.StringPointerValue(instance.PrivateIpAddress)
+	model.PublicIP = types.StringPointerValue(instance.PublicIpAddress)
+
+	// 如果用户没有提供 security_group_ids，从 API 读取并填充
+	if model.SecurityGroupIDs.IsNull() || model.SecurityGroupIDs.IsUnknown() {
+		sgIDs := make([]string, 0, len(instance.SecurityGroups))
+		for _, sg := range instance.SecurityGroups {
+			if sg.GroupId != nil {
+				sgIDs = append(sgIDs, aws.StringValue(sg.GroupId))
+			}
+		}
+		sgList, d := types.ListValueFrom(ctx, types.StringType, sgIDs)
+		diags.Append(d...)
+		if !diags.HasError() {
+			model.SecurityGroupIDs = sgList
+		}
+	}
+
+	return diags
+}
+
+// flattenBlockDeviceMappings 将实例挂载的 EBS 卷转换为块设备映射列表
+// 根设备排在第一位（与 Create 中第一个元素为系统盘的约定一致），
+// 数据盘按 prior 中的顺序排列，prior 中没有的设备按设备名称追加在末尾
+func flattenBlockDeviceMappings(ctx context.Context, instance *ec2.Instance, volumes map[string]*ec2.Volume, prior types.List) (types.List, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	rootDeviceName := aws.StringValue(instance.RootDeviceName)
+	if rootDeviceName == "" {
+		rootDeviceName = "/dev/vda"
+	}
+
+	devices := make(map[string]BlockDeviceMappingModel)
+	for _, bdm := range instance.BlockDeviceMappings {
+		if bdm.Ebs == nil {
+			continue
+		}
+		volume, ok := volumes[aws.StringValue(bdm.Ebs.VolumeId)]
+		if !ok {
+			continue
+		}
+
+		deviceName := aws.StringValue(bdm.DeviceName)
+		devices[deviceName] = BlockDeviceMappingModel{
+			VolumeSize: types.Int64Value(aws.Int64Value(volume.Size)),
+			VolumeType: types.StringPointerValue(volume.VolumeType),
+			DeviceName: types.StringValue(deviceName),
+		}
+	}
+
+	var priorList []BlockDeviceMappingModel
+	if !prior.IsNull() && !prior.IsUnknown() {
+		diags.Append(prior.ElementsAs(ctx, &priorList, false)...)
+		if diags.HasError() {
+			return prior, diags
+		}
+	}
+
+	bdmList := make([]BlockDeviceMappingModel, 0, len(devices))
+	if root, ok := devices[rootDeviceName]; ok {
+		bdmList = append(bdmList, root)
+		delete(devices, rootDeviceName)
+	}
+	for _, bdm := range priorList {
+		if device, ok := devices[bdm.DeviceName.ValueString()]; ok {
+			bdmList = append(bdmList, device)
+			delete(devices, bdm.DeviceName.ValueString())
+		}
+	}
+	remaining := make([]string, 0, len(devices))
+	for deviceName := range devices {
+		remaining = append(remaining, deviceName)
+	}
+	sort.Strings(remaining)
+	for _, deviceName := range remaining {
+		bdmList = append(bdmList, devices[deviceName])
+	}
+
+	result, d := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: blockDeviceMappingAttrTypes}, bdmList)
+	diags.Append(d...)
+	return result, diags
+}
+
+// modifyInstanceType 停止实例、修改实例类型，start 为 true 时重新启动实例
+func modifyInstanceType(ctx context.Context, conn *ec2.EC2, id, instanceType string, start bool, timeout time.Duration) error {
+	if err := stopInstance(ctx, conn, id, timeout); err != nil {
+		return err
+	}
+
+	_, err := conn.ModifyInstanceAttributeWithContext(ctx, &ec2.ModifyInstanceAttributeInput{
+		InstanceId:   aws.String(id),
+		InstanceType: &ec2.AttributeValue{Value: aws.String(instanceType)},
+	})
+	if err != nil {
+		return fmt.Errorf("修改实例类型失败: %w", err)
+	}
+
+	if !start {
+		return nil
+	}
+
+	return startInstance(ctx, conn, id, timeout)
+}
+
+// reconcileInstanceState 将实例调整到期望的电源状态（running 或 stopped）
+func reconcileInstanceState(ctx context.Context, conn *ec2.EC2, id, desired string, timeout time.Duration) error {
+	switch desired {
+	case ec2.InstanceStateNameRunning:
+		return startInstance(ctx, conn, id, timeout)
+	case ec2.InstanceStateNameStopped:
+		return stopInstance(ctx, conn, id, timeout)
+	}
+	return nil
+}
+
+// startInstance 启动实例并等待其进入 running 状态
+func startInstance(ctx context.Context, conn *ec2.EC2, id string, timeout time.Duration) error {
+	_, err := conn.StartInstancesWithContext(ctx, &ec2.StartInstancesInput{
+		InstanceIds: []*string{aws.String(id)},
+	})
+	if err != nil {
+		return fmt.Errorf("启动实例失败: %w", err)
+	}
+
+	err = conn.WaitUntilInstanceRunningWithContext(ctx, &ec2.DescribeInstancesInput{
+		InstanceIds: []*string{aws.String(id)},
+	}, waiterOptions(timeout)...)
+	if err != nil {
+		return fmt.Errorf("等待实例运行失败: %w", err)
+	}
+
+	return nil
+}
+
+// stopInstance 停止实例并等待其进入 stopped 状态
+func stopInstance(ctx context.Context, conn *ec2.EC2, id string, timeout time.Duration) error {
+	_, err := conn.StopInstancesWithContext(ctx, &ec2.StopInstancesInput{
+		InstanceIds: []*string{aws.String(id)},
+	})
+	if err != nil {
+		return fmt.Errorf("停止实例失败: %w", err)
+	}
+
+	err = conn.WaitUntilInstanceStoppedWithContext(ctx, &ec2.DescribeInstancesInput{
+		InstanceIds: []*string{aws.String(id)},
+	}, waiterOptions(timeout)...)
+	if err != nil {
+		return fmt.Errorf("等待实例停止失败: %w", err)
+	}
+
+	return nil
+}
+
+// terminateInstances 逐个终止实例并等待其进入 terminated 状态，实例不存在时视为成功
+func terminateInstances(ctx context.Context, conn *ec2.EC2, ids []string, timeout time.Duration) error {
+	for _, id := range ids {
+		_, err := conn.TerminateInstancesWithContext(ctx, &ec2.TerminateInstancesInput{
+			InstanceIds: []*string{aws.String(id)},
+		})
+		if err != nil && !isNotFoundError(err) {
+			return fmt.Errorf("终止实例 %s 失败: %w", id, err)
+		}
+	}
+
+	for _, id := range ids {
+		if err := waitInstanceTerminated(ctx, conn, id, timeout); err != nil {
+			return fmt.Errorf("等待实例 %s 终止失败: %w", id, err)
+		}
+	}
+
+	return nil
+}
+
+// updateInstanceProtection 更新终止保护和停止保护，enable 为 true 时只处理开启，为 false 时只处理关闭
+func updateInstanceProtection(ctx context.Context, conn *ec2.EC2, id string, plan, state InstanceResourceModel, enable bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for _, p := range []struct {
+		attribute   string
+		planValue   types.Bool
+		stateValue  types.Bool
+		description string
+	}{
+		{ec2.InstanceAttributeNameDisableApiTermination, plan.DisableApiTermination, state.DisableApiTermination, "终止保护"},
+		{ec2.InstanceAttributeNameDisableApiStop, plan.DisableApiStop, state.DisableApiStop, "停止保护"},
+	} {
+		if p.planValue.IsUnknown() || p.planValue.Equal(p.stateValue) || p.planValue.ValueBool() != enable {
+			continue
+		}
+
+		tflog.Debug(ctx, "修改实例保护设置", map[string]interface{}{
+			"instance_id": id,
+			"attribute":   p.attribute,
+			"value":       enable,
+		})
+
+		if err := modifyInstanceProtection(ctx, conn, id, p.attribute, enable); err != nil {
+			diags.AddError(
+				"修改实例"+p.description+"失败",
+				"无法修改实例 "+id+" 的"+p.description+": "+err.Error(),
+			)
+			return diags
+		}
+	}
+
+	return diags
+}
+
+// modifyInstanceProtection 修改实例的 disableApiTermination 或 disableApiStop 属性
+func modifyInstanceProtection(ctx context.Context, conn *ec2.EC2, id, attribute string, value bool) error {
+	input := &ec2.ModifyInstanceAttributeInput{
+		InstanceId: aws.String(id),
+	}
+	switch attribute {
+	case ec2.InstanceAttributeNameDisableApiTermination:
+		input.DisableApiTermination = &ec2.AttributeBooleanValue{Value: aws.Bool(value)}
+	case ec2.InstanceAttributeNameDisableApiStop:
+		input.DisableApiStop = &ec2.AttributeBooleanValue{Value: aws.Bool(value)}
+	default:
+		return fmt.Errorf("不支持的实例属性: %s", attribute)
+	}
+
+	_, err := conn.ModifyInstanceAttributeWithContext(ctx, input)
+	return err
+}
+
+// findInstanceProtection 查询实例的 disableApiTermination 或 disableApiStop 属性
+func findInstanceProtection(ctx context.Context, conn *ec2.EC2, id, attribute string) (bool, error) {
+	output, err := conn.DescribeInstanceAttributeWithContext(ctx, &ec2.DescribeInstanceAttributeInput{
+		InstanceId: aws.String(id),
+		Attribute:  aws.String(attribute),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	switch attribute {
+	case ec2.InstanceAttributeNameDisableApiTermination:
+		if output.DisableApiTermination != nil {
+			return aws.BoolValue(output.DisableApiTermination.Value), nil
+		}
+	case ec2.InstanceAttributeNameDisableApiStop:
+		if output.DisableApiStop != nil {
+			return aws.BoolValue(output.DisableApiStop.Value), nil
+		}
+	}
+	return false, nil
+}
+
+// tagsToMap 将 API 返回的标签列表转换为映射
+func tagsToMap(tags []*ec2.Tag) map[string]string {
+	result := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		if tag.Key != nil && tag.Value != nil {
+			result[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+	}
+	return result
+}
+
+// updateTags 比较新旧标签，删除移除的键并创建新增或修改的键
+func updateTags(ctx context.Context, conn *ec2.EC2, id string, oldTags, newTags map[string]string) error {
+	var removed []*ec2.Tag
+	for k := range oldTags {
+		if _, ok := newTags[k]; !ok {
+			removed = append(removed, &ec2.Tag{Key: aws.String(k)})
+		}
+	}
+
+	var updated []*ec2.Tag
+	for k, v := range newTags {
+		if old, ok := oldTags[k]; !ok || old != v {
+			updated = append(updated, &ec2.Tag{Key: aws.String(k), Value: aws.String(v)})
+		}
+	}
+
+	if len(removed) > 0 {
+		tflog.Debug(ctx, "删除资源标签", map[string]interface{}{
+			"resource_id": id,
+			"count":       len(removed),
+		})
+
+		_, err := conn.DeleteTagsWithContext(ctx, &ec2.DeleteTagsInput{
+			Resources: []*string{aws.String(id)},
+			Tags:      removed,
+		})
+		if err != nil {
+			return fmt.Errorf("删除标签失败: %w", err)
+		}
+	}
+
+	if len(updated) > 0 {
+		tflog.Debug(ctx, "创建资源标签", map[string]interface{}{
+			"resource_id": id,
+			"count":       len(updated),
+		})
+
+		_, err := conn.CreateTagsWithContext(ctx, &ec2.CreateTagsInput{
+			Resources: []*string{aws.String(id)},
+			Tags:      updated,
+		})
+		if err != nil {
+			return fmt.Errorf("创建标签失败: %w", err)
+		}
+	}
+
+	return nil
+}
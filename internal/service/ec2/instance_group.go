@@ -0,0 +1,906 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package ec2
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/mulei1288/terraform-provider-bingocloud/internal/conns"
+	tftags "github.com/mulei1288/terraform-provider-bingocloud/internal/tags"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/service/ec2"
+)
+
+// 确保实现了必需的接口
+var _ resource.Resource = &InstanceGroupResource{}
+var _ resource.ResourceWithModifyPlan = &InstanceGroupResource{}
+var _ resource.ResourceWithValidateConfig = &InstanceGroupResource{}
+
+// 默认超时时间
+const (
+	instanceGroupCreateTimeout = 20 * time.Minute
+	instanceGroupUpdateTimeout = 20 * time.Minute
+	instanceGroupDeleteTimeout = 30 * time.Minute
+)
+
+// InstanceGroupResource 定义实例组资源实现，使用相同参数批量创建多个实例
+type InstanceGroupResource struct {
+	client *conns.BingoCloudClient
+}
+
+// InstanceGroupResourceModel 描述实例组资源数据模型
+type InstanceGroupResourceModel struct {
+	// 必需参数
+	InstanceCount       types.Int64  `tfsdk:"instance_count"`
+	ImageId             types.String `tfsdk:"image_id"`
+	InstanceType        types.String `tfsdk:"instance_type"`
+	SubnetID            types.String `tfsdk:"subnet_id"`
+	Password            types.String `tfsdk:"password"`
+	BlockDeviceMappings types.List   `tfsdk:"block_device_mappings"`
+
+	// 可选参数
+	InstanceName     types.String `tfsdk:"instance_name"`
+	SecurityGroupIDs types.List   `tfsdk:"security_group_ids"`
+	KeyName          types.String `tfsdk:"key_name"`
+	UserData         types.String `tfsdk:"user_data"`
+	Tags             types.Map    `tfsdk:"tags"`
+
+	// 计算属性
+	ID          types.String `tfsdk:"id"`
+	TagsAll     types.Map    `tfsdk:"tags_all"`
+	InstanceIDs types.List   `tfsdk:"instance_ids"`
+	PrivateIPs  types.List   `tfsdk:"private_ips"`
+	States      types.List   `tfsdk:"states"`
+
+	// 超时配置
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// NewInstanceGroupResource 创建新的实例组资源
+func NewInstanceGroupResource() resource.Resource {
+	return &InstanceGroupResource{}
+}
+
+// Metadata 返回资源类型名称
+func (r *InstanceGroupResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_instance_group"
+}
+
+// Configure 配置资源，接收 Provider 传递的客户端
+func (r *InstanceGroupResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*conns.BingoCloudClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"意外的资源配置类型",
+			fmt.Sprintf("期望 *conns.BingoCloudClient，得到: %T。请向 provider 开发者报告此问题。", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+// Schema 定义资源的属性架构
+func (r *InstanceGroupResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "使用相同参数批量管理 BingoCloud 虚拟机实例，修改 `instance_count` 时原地扩缩容",
+
+		Attributes: map[string]schema.Attribute{
+			// 必需参数
+			"instance_count": schema.Int64Attribute{
+				MarkdownDescription: "实例个数，缩容时优先终止最后创建的实例",
+				Required:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"image_id": schema.StringAttribute{
+				MarkdownDescription: "镜像 ID，用于创建虚拟机",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"instance_type": schema.StringAttribute{
+				MarkdownDescription: "实例类型（如 t2.micro, m5.large），修改时逐个停机变更",
+				Required:            true,
+			},
+			"subnet_id": schema.StringAttribute{
+				MarkdownDescription: "子网 ID",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"password": schema.StringAttribute{
+				MarkdownDescription: "实例登录密码，只写属性，不会保存到计划和状态中（需要 Terraform 1.11 及以上版本）。修改后只对扩容新建的实例生效",
+				Required:            true,
+				Sensitive:           true,
+				WriteOnly:           true,
+			},
+			"block_device_mappings": schema.ListNestedAttribute{
+				MarkdownDescription: "块设备映射配置列表，第一个元素为系统盘，后续为数据盘",
+				Required:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"volume_size": schema.Int64Attribute{
+							MarkdownDescription: "磁盘大小（GB）",
+							Required:            true,
+						},
+						"volume_type": schema.StringAttribute{
+							MarkdownDescription: "磁盘类型（如 gp2, io1）",
+							Required:            true,
+						},
+						"device_name": schema.StringAttribute{
+							MarkdownDescription: "设备名称（如 /dev/vda, /dev/vdb），第一个元素默认 /dev/vda",
+							Optional:            true,
+							Computed:            true,
+						},
+					},
+				},
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+
+			// 可选参数
+			"instance_name": schema.StringAttribute{
+				MarkdownDescription: "实例名称，所有实例使用相同的 Name 标签，修改后原地生效。`tags` 中不能再配置 Name",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"security_group_ids": schema.ListAttribute{
+				MarkdownDescription: "安全组 ID 列表",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"key_name": schema.StringAttribute{
+				MarkdownDescription: "SSH 密钥对名称",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"user_data": schema.StringAttribute{
+				MarkdownDescription: "用户数据脚本（Base64 编码）",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"tags": tftags.TagsAttribute(),
+
+			// 计算属性（只读）
+			"id": schema.StringAttribute{
+				MarkdownDescription: "实例组 ID（首次创建时的预留 ID）",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tags_all": tftags.TagsAllAttribute(),
+			"instance_ids": schema.ListAttribute{
+				MarkdownDescription: "实例 ID 列表，按创建顺序排列",
+				ElementType:         types.StringType,
+				Computed:            true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"private_ips": schema.ListAttribute{
+				MarkdownDescription: "私有 IP 地址列表，与 instance_ids 一一对应",
+				ElementType:         types.StringType,
+				Computed:            true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"states": schema.ListAttribute{
+				MarkdownDescription: "实例状态列表，与 instance_ids 一一对应",
+				ElementType:         types.StringType,
+				Computed:            true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// Create 批量创建实例
+func (r *InstanceGroupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan InstanceGroupResourceModel
+
+	// 读取 Terraform 计划数据
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, instanceGroupCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	conn := r.client.EC2Client()
+
+	// 密码为只写属性，只能从配置中读取
+	var password types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("password"), &password)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	runInput, diags := expandInstanceGroupRunInput(ctx, &plan, plan.InstanceCount.ValueInt64(), password.ValueString())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "批量创建 BingoCloud 实例", map[string]interface{}{
+		"image_id":       plan.ImageId.ValueString(),
+		"instance_type":  plan.InstanceType.ValueString(),
+		"instance_count": plan.InstanceCount.ValueInt64(),
+	})
+
+	result, err := conn.RunInstancesWithContext(ctx, runInput)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"创建实例组失败",
+			"无法创建实例: "+err.Error(),
+		)
+		return
+	}
+
+	ids := make([]string, 0, len(result.Instances))
+	for _, instance := range result.Instances {
+		ids = append(ids, aws.StringValue(instance.InstanceId))
+	}
+	if len(ids) == 0 {
+		resp.Diagnostics.AddError(
+			"创建实例组失败",
+			"API 返回空实例列表",
+		)
+		return
+	}
+
+	plan.ID = types.StringValue(aws.StringValue(result.ReservationId))
+	if plan.ID.ValueString() == "" {
+		plan.ID = types.StringValue(ids[0])
+	}
+
+	// 立即保存实例 ID，后续步骤失败时资源会被标记为 tainted
+	instanceIDs, diags := types.ListValueFrom(ctx, types.StringType, ids)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), plan.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("instance_ids"), instanceIDs)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("timeouts"), plan.Timeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// 等待全部实例运行
+	if err := waitInstancesRunning(ctx, conn, ids, createTimeout); err != nil {
+		resp.Diagnostics.AddError(
+			"等待实例运行失败",
+			"实例组创建成功但未能全部进入运行状态: "+waitErrorDetail(ctx, "创建", createTimeout, err, instancesLastState(conn, ids)),
+		)
+		r.handleCreateFailure(ctx, ids, resp)
+		return
+	}
+
+	instances, err := findInstancesByIDs(ctx, conn, ids)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"读取实例详情失败",
+			"实例组创建成功但无法读取详细信息: "+err.Error(),
+		)
+		r.handleCreateFailure(ctx, ids, resp)
+		return
+	}
+
+	resp.Diagnostics.Append(flattenInstanceGroup(ctx, ids, instances, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// 未配置实例名称时以平台生成的 Name 标签为准
+	if plan.InstanceName.IsUnknown() {
+		plan.InstanceName = instanceGroupNameValue(ids, instances)
+	}
+
+	tflog.Trace(ctx, "创建实例组成功")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// handleCreateFailure 处理 RunInstances 成功之后的创建失败，行为与 bingocloud_instance 一致
+func (r *InstanceGroupResource) handleCreateFailure(ctx context.Context, ids []string, resp *resource.CreateResponse) {
+	if !r.client.TerminateOnCreateFailure {
+		resp.Diagnostics.AddWarning(
+			"实例组已标记为 tainted",
+			fmt.Sprintf("实例 %v 已创建但未能完成初始化，已保存到状态中，下次 apply 时将被替换", ids),
+		)
+		return
+	}
+
+	// 原 context 可能已超时，使用独立的 context 进行清理
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), instanceGroupDeleteTimeout)
+	defer cancel()
+
+	tflog.Debug(ctx, "终止创建失败的实例组", map[string]interface{}{
+		"instance_ids": ids,
+	})
+
+	if err := terminateInstances(ctx, r.client.EC2Client(), ids, instanceGroupDeleteTimeout); err != nil {
+		resp.Diagnostics.AddError(
+			"回滚实例组失败",
+			"无法终止创建失败的实例，实例已保存到状态中并将被标记为 tainted: "+err.Error(),
+		)
+		return
+	}
+
+	resp.State.RemoveResource(ctx)
+}
+
+// Read 读取实例组状态，已不存在的实例会从列表中移除
+func (r *InstanceGroupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state InstanceGroupResourceModel
+
+	// 读取 Terraform 状态数据
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var ids []string
+	resp.Diagnostics.Append(state.InstanceIDs.ElementsAs(ctx, &ids, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	instances, err := findInstancesByIDs(ctx, r.client.EC2Client(), ids)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"读取实例组失败",
+			"无法读取实例组 "+state.ID.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	// 只保留仍然存在的实例，数量变化会在下次 plan 中体现为扩容
+	existing := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := instances[id]; ok {
+			existing = append(existing, id)
+		}
+	}
+	if len(existing) == 0 {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.InstanceCount = types.Int64Value(int64(len(existing)))
+	state.InstanceType = types.StringValue(aws.StringValue(instances[existing[0]].InstanceType))
+
+	resp.Diagnostics.Append(flattenInstanceGroup(ctx, existing, instances, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	priorTags, diags := instanceGroupTags(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tagMap := instanceGroupMemberTags(existing, instances, priorTags)
+	state.InstanceName = tftags.NameValue(tagMap)
+	tagsValue, tagsAllValue, diags := tftags.Flatten(ctx, tagMap, state.Tags, r.client.DefaultTagsConfig, r.client.IgnoreTagsConfig, tftags.NameKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	state.Tags = tagsValue
+	state.TagsAll = tagsAllValue
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update 更新实例组：先缩容，再原地修改现有实例，最后按新配置扩容
+func (r *InstanceGroupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state InstanceGroupResourceModel
+
+	// 读取计划数据和当前状态
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, instanceGroupUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	conn := r.client.EC2Client()
+
+	var ids []string
+	resp.Diagnostics.Append(state.InstanceIDs.ElementsAs(ctx, &ids, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	desired := int(plan.InstanceCount.ValueInt64())
+
+	// 缩容：终止最后创建的实例
+	if desired < len(ids) {
+		removed := ids[desired:]
+
+		tflog.Debug(ctx, "实例组缩容", map[string]interface{}{
+			"instance_ids": removed,
+		})
+
+		if err := terminateInstances(ctx, conn, removed, updateTimeout); err != nil {
+			resp.Diagnostics.AddError(
+				"实例组缩容失败",
+				"无法终止实例: "+waitErrorDetail(ctx, "更新", updateTimeout, err, instancesLastState(conn, removed)),
+			)
+			r.setPartialState(ctx, resp, state, ids)
+			return
+		}
+		ids = ids[:desired]
+	}
+
+	// 实例类型：逐个停机修改
+	if !plan.InstanceType.Equal(state.InstanceType) {
+		for _, id := range ids {
+			tflog.Debug(ctx, "修改实例类型", map[string]interface{}{
+				"instance_id":   id,
+				"instance_type": plan.InstanceType.ValueString(),
+			})
+
+			if err := modifyInstanceType(ctx, conn, id, plan.InstanceType.ValueString(), true, updateTimeout); err != nil {
+				resp.Diagnostics.AddError(
+					"修改实例类型失败",
+					"无法修改实例 "+id+" 的类型: "+waitErrorDetail(ctx, "更新", updateTimeout, err, instanceLastState(conn, id)),
+				)
+				r.setPartialState(ctx, resp, state, ids)
+				return
+			}
+		}
+	}
+
+	// 标签：实例名称作为 Name 标签一并比较
+	if !plan.TagsAll.Equal(state.TagsAll) || !plan.InstanceName.Equal(state.InstanceName) {
+		oldTags, d := instanceGroupTags(ctx, state)
+		resp.Diagnostics.Append(d...)
+		newTags, d := instanceGroupTags(ctx, plan)
+		resp.Diagnostics.Append(d...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		for _, id := range ids {
+			if err := updateTags(ctx, conn, id, oldTags, newTags); err != nil {
+				resp.Diagnostics.AddError(
+					"更新实例标签失败",
+					"无法更新实例 "+id+" 的标签: "+err.Error(),
+				)
+				r.setPartialState(ctx, resp, state, ids)
+				return
+			}
+		}
+	}
+
+	// 安全组
+	if !plan.SecurityGroupIDs.IsUnknown() && !plan.SecurityGroupIDs.Equal(state.SecurityGroupIDs) {
+		var sgIDs []string
+		resp.Diagnostics.Append(plan.SecurityGroupIDs.ElementsAs(ctx, &sgIDs, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		for _, id := range ids {
+			_, err := conn.ModifyInstanceAttributeWithContext(ctx, &ec2.ModifyInstanceAttributeInput{
+				InstanceId: aws.String(id),
+				Groups:     aws.StringSlice(sgIDs),
+			})
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"修改实例安全组失败",
+					"无法修改实例 "+id+" 的安全组: "+err.Error(),
+				)
+				r.setPartialState(ctx, resp, state, ids)
+				return
+			}
+		}
+	}
+
+	// 扩容：按当前配置创建新实例
+	if desired > len(ids) {
+		// 密码为只写属性，只能从配置中读取
+		var password types.String
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("password"), &password)...)
+		if resp.Diagnostics.HasError() {
+			r.setPartialState(ctx, resp, state, ids)
+			return
+		}
+
+		runInput, diags := expandInstanceGroupRunInput(ctx, &plan, int64(desired-len(ids)), password.ValueString())
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		tflog.Debug(ctx, "实例组扩容", map[string]interface{}{
+			"instance_count": desired - len(ids),
+		})
+
+		result, err := conn.RunInstancesWithContext(ctx, runInput)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"实例组扩容失败",
+				"无法创建实例: "+err.Error(),
+			)
+			r.setPartialState(ctx, resp, state, ids)
+			return
+		}
+
+		added := make([]string, 0, len(result.Instances))
+		for _, instance := range result.Instances {
+			added = append(added, aws.StringValue(instance.InstanceId))
+		}
+		ids = append(ids, added...)
+
+		if err := waitInstancesRunning(ctx, conn, added, updateTimeout); err != nil {
+			resp.Diagnostics.AddError(
+				"等待实例运行失败",
+				"扩容的实例未能全部进入运行状态: "+waitErrorDetail(ctx, "更新", updateTimeout, err, instancesLastState(conn, added)),
+			)
+			r.setPartialState(ctx, resp, state, ids)
+			return
+		}
+	}
+
+	// 重新读取全部实例以刷新计算属性
+	instances, err := findInstancesByIDs(ctx, conn, ids)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"读取实例详情失败",
+			"实例组更新成功但无法读取详细信息: "+err.Error(),
+		)
+		r.setPartialState(ctx, resp, state, ids)
+		return
+	}
+
+	resp.Diagnostics.Append(flattenInstanceGroup(ctx, ids, instances, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "更新实例组成功")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// setPartialState 更新中途失败时基于原状态保存当前的实例 ID 列表，
+// 确保已创建的实例不会游离在状态之外，其余计算属性在下次刷新时补全
+func (r *InstanceGroupResource) setPartialState(ctx context.Context, resp *resource.UpdateResponse, state InstanceGroupResourceModel, ids []string) {
+	instanceIDs, diags := types.ListValueFrom(ctx, types.StringType, ids)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() {
+		return
+	}
+
+	state.InstanceIDs = instanceIDs
+	state.InstanceCount = types.Int64Value(int64(len(ids)))
+	state.PrivateIPs = types.ListNull(types.StringType)
+	state.States = types.ListNull(types.StringType)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Delete 终止实例组中的全部实例
+func (r *InstanceGroupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state InstanceGroupResourceModel
+
+	// 读取 Terraform 状态数据
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, instanceGroupDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	var ids []string
+	resp.Diagnostics.Append(state.InstanceIDs.ElementsAs(ctx, &ids, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn := r.client.EC2Client()
+	if err := terminateInstances(ctx, conn, ids, deleteTimeout); err != nil {
+		resp.Diagnostics.AddError(
+			"删除实例组失败",
+			"无法删除实例组 "+state.ID.ValueString()+": "+waitErrorDetail(ctx, "删除", deleteTimeout, err, instancesLastState(conn, ids)),
+		)
+		return
+	}
+
+	tflog.Trace(ctx, "删除实例组成功")
+}
+
+// ValidateConfig 校验配置：Name 标签只能通过 instance_name 配置
+func (r *InstanceGroupResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config InstanceGroupResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(tftags.ValidateNameTag(ctx, config.Tags, "instance_name")...)
+}
+
+// ModifyPlan 调整计划：计算 tags_all；实例数量变化时实例列表相关的计算属性在 apply 后才能确定
+func (r *InstanceGroupResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// 销毁时无需调整
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	// 合并 provider 的默认标签，创建时同样需要
+	if r.client != nil {
+		resp.Diagnostics.Append(tftags.ModifyPlan(ctx, r.client.DefaultTagsConfig, r.client.IgnoreTagsConfig, &resp.Plan, tftags.NameKey)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// 创建时无需进一步调整
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	var plan, state InstanceGroupResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.InstanceCount.Equal(state.InstanceCount) {
+		for _, name := range []string{"instance_ids", "private_ips", "states"} {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(name), types.ListUnknown(types.StringType))...)
+		}
+	}
+}
+
+// expandInstanceGroupRunInput 构建创建 count 个实例的 RunInstances 请求，password 从配置中读取
+func expandInstanceGroupRunInput(ctx context.Context, plan *InstanceGroupResourceModel, count int64, password string) (*ec2.RunInstancesInput, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	runInput := &ec2.RunInstancesInput{
+		ImageId:      aws.String(plan.ImageId.ValueString()),
+		InstanceType: aws.String(plan.InstanceType.ValueString()),
+		SubnetId:     aws.String(plan.SubnetID.ValueString()),
+		MinCount:     aws.Int64(count),
+		MaxCount:     aws.Int64(count),
+		Password:     aws.String(password),
+	}
+
+	// 实例名称同时作为 RunInstances 参数和 Name 标签传递
+	if !plan.InstanceName.IsNull() && !plan.InstanceName.IsUnknown() {
+		runInput.InstanceName = aws.String(plan.InstanceName.ValueString())
+	}
+
+	// 配置块设备映射，并更新 plan 中的 BlockDeviceMappings（包含默认值）
+	blockDeviceMappings, bdmList, d := expandBlockDeviceMappings(ctx, plan.BlockDeviceMappings)
+	diags.Append(d...)
+	if diags.HasError() {
+		return nil, diags
+	}
+	plan.BlockDeviceMappings = bdmList
+	runInput.BlockDeviceMappings = blockDeviceMappings
+
+	// 配置安全组
+	if !plan.SecurityGroupIDs.IsNull() && !plan.SecurityGroupIDs.IsUnknown() {
+		var sgIDs []string
+		diags.Append(plan.SecurityGroupIDs.ElementsAs(ctx, &sgIDs, false)...)
+		if diags.HasError() {
+			return nil, diags
+		}
+		runInput.SecurityGroupIds = aws.StringSlice(sgIDs)
+	}
+
+	// 配置密钥对
+	if !plan.KeyName.IsNull() {
+		runInput.KeyName = aws.String(plan.KeyName.ValueString())
+	}
+
+	// 配置用户数据
+	if !plan.UserData.IsNull() {
+		runInput.UserData = aws.String(plan.UserData.ValueString())
+	}
+
+	// 配置标签
+	tagSpecifications, d := expandInstanceTagSpecifications(ctx, plan.InstanceName, plan.TagsAll)
+	diags.Append(d...)
+	if diags.HasError() {
+		return nil, diags
+	}
+	runInput.TagSpecifications = tagSpecifications
+
+	return runInput, diags
+}
+
+// instanceGroupTags 返回实例组应用到每个实例的完整标签（包含默认标签和 Name 标签）
+func instanceGroupTags(ctx context.Context, model InstanceGroupResourceModel) (map[string]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	tags := make(map[string]string)
+	if !model.TagsAll.IsNull() && !model.TagsAll.IsUnknown() {
+		diags.Append(model.TagsAll.ElementsAs(ctx, &tags, false)...)
+	}
+	if !model.InstanceName.IsNull() && !model.InstanceName.IsUnknown() {
+		tags[tftags.NameKey] = model.InstanceName.ValueString()
+	}
+
+	return tags, diags
+}
+
+// instanceGroupNameValue 返回所有实例共同的 Name 标签，各实例的 Name 标签不一致时返回 null
+func instanceGroupNameValue(ids []string, instances map[string]*ec2.Instance) types.String {
+	name := types.StringNull()
+	for i, id := range ids {
+		instance, ok := instances[id]
+		if !ok {
+			return types.StringNull()
+		}
+		value := tftags.NameValue(tagsToMap(instance.Tags))
+		if i > 0 && !value.Equal(name) {
+			return types.StringNull()
+		}
+		name = value
+	}
+	return name
+}
+
+// instanceGroupMemberTags 汇总实例组成员的标签：状态中已有的标签只有在所有成员上取值一致时才保留，
+// 任一成员上额外出现的标签都会被加入，这样任何成员的标签漂移都会在下次 plan 中体现
+func instanceGroupMemberTags(ids []string, instances map[string]*ec2.Instance, prior map[string]string) map[string]string {
+	memberTags := make([]map[string]string, 0, len(ids))
+	for _, id := range ids {
+		if instance, ok := instances[id]; ok {
+			memberTags = append(memberTags, tagsToMap(instance.Tags))
+		}
+	}
+
+	merged := make(map[string]string)
+	for _, tags := range memberTags {
+		for k, v := range tags {
+			if _, ok := prior[k]; ok {
+				continue
+			}
+			if _, ok := merged[k]; !ok {
+				merged[k] = v
+			}
+		}
+	}
+
+	for k, v := range prior {
+		consistent := true
+		for _, tags := range memberTags {
+			if current, ok := tags[k]; !ok || current != v {
+				consistent = false
+				break
+			}
+		}
+		if consistent {
+			merged[k] = v
+		}
+	}
+
+	return merged
+}
+
+// flattenInstanceGroup 按 ids 的顺序将实例信息写入模型中的列表属性
+func flattenInstanceGroup(ctx context.Context, ids []string, instances map[string]*ec2.Instance, model *InstanceGroupResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	privateIPs := make([]string, 0, len(ids))
+	states := make([]string, 0, len(ids))
+	for _, id := range ids {
+		instance, ok := instances[id]
+		if !ok {
+			privateIPs = append(privateIPs, "")
+			states = append(states, "unknown")
+			continue
+		}
+		privateIPs = append(privateIPs, aws.StringValue(instance.PrivateIpAddress))
+		states = append(states, aws.StringValue(instance.State.Name))
+	}
+
+	instanceIDs, d := types.ListValueFrom(ctx, types.StringType, ids)
+	diags.Append(d...)
+	privateIPList, d := types.ListValueFrom(ctx, types.StringType, privateIPs)
+	diags.Append(d...)
+	stateList, d := types.ListValueFrom(ctx, types.StringType, states)
+	diags.Append(d...)
+	if diags.HasError() {
+		return diags
+	}
+
+	model.InstanceIDs = instanceIDs
+	model.PrivateIPs = privateIPList
+	model.States = stateList
+
+	// 如果用户没有提供 security_group_ids，从第一个实例读取并填充
+	if (model.SecurityGroupIDs.IsNull() || model.SecurityGroupIDs.IsUnknown()) && len(ids) > 0 {
+		sgIDs := []string{}
+		if instance, ok := instances[ids[0]]; ok {
+			for _, sg := range instance.SecurityGroups {
+				if sg.GroupId != nil {
+					sgIDs = append(sgIDs, aws.StringValue(sg.GroupId))
+				}
+			}
+		}
+		sgList, d := types.ListValueFrom(ctx, types.StringType, sgIDs)
+		diags.Append(d...)
+		if !diags.HasError() {
+			model.SecurityGroupIDs = sgList
+		}
+	}
+
+	return diags
+}
@@ -0,0 +1,76 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package ec2_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/mulei1288/terraform-provider-bingocloud/internal/acctest"
+)
+
+// testAccInstanceGroupConfig 生成实例组资源的测试配置
+func testAccInstanceGroupConfig(name string, count int) string {
+	return acctest.ProviderConfig() + fmt.Sprintf(`
+resource "bingocloud_instance_group" "test" {
+  instance_count = %[4]d
+  image_id       = %[1]q
+  instance_type  = "m1.small"
+  subnet_id      = %[2]q
+  password       = "Test@123456"
+  instance_name  = %[3]q
+
+  block_device_mappings = [
+    {
+      volume_size = 20
+      volume_type = "standard"
+    }
+  ]
+
+  tags = {
+    Environment = "test"
+  }
+}
+`, os.Getenv("BINGOCLOUD_TEST_AMI"), os.Getenv("BINGOCLOUD_TEST_SUBNET"), name, count)
+}
+
+// TestAccInstanceGroupResource_scale 测试实例组的创建和原地扩缩容
+func TestAccInstanceGroupResource_scale(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// 创建 2 个实例
+			{
+				Config: testAccInstanceGroupConfig("test-instance-group", 2),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("bingocloud_instance_group.test", "instance_count", "2"),
+					resource.TestCheckResourceAttr("bingocloud_instance_group.test", "instance_ids.#", "2"),
+					resource.TestCheckResourceAttr("bingocloud_instance_group.test", "private_ips.#", "2"),
+					resource.TestCheckResourceAttr("bingocloud_instance_group.test", "states.0", "running"),
+					resource.TestCheckResourceAttrSet("bingocloud_instance_group.test", "id"),
+				),
+			},
+			// 扩容到 3 个实例
+			{
+				Config: testAccInstanceGroupConfig("test-instance-group", 3),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("bingocloud_instance_group.test", "instance_count", "3"),
+					resource.TestCheckResourceAttr("bingocloud_instance_group.test", "instance_ids.#", "3"),
+				),
+			},
+			// 缩容到 1 个实例
+			{
+				Config: testAccInstanceGroupConfig("test-instance-group", 1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("bingocloud_instance_group.test", "instance_count", "1"),
+					resource.TestCheckResourceAttr("bingocloud_instance_group.test", "instance_ids.#", "1"),
+					resource.TestCheckResourceAttr("bingocloud_instance_group.test", "states.#", "1"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,145 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package ec2
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// instanceResourceModelV0 描述版本 0 的状态数据模型
+// 早期版本使用 system_disk_size 配置系统盘，之后改为 block_device_mappings，两者都可能出现在版本 0 的状态中
+type instanceResourceModelV0 struct {
+	ImageId             types.String `tfsdk:"image_id"`
+	InstanceType        types.String `tfsdk:"instance_type"`
+	SubnetID            types.String `tfsdk:"subnet_id"`
+	Password            types.String `tfsdk:"password"`
+	SystemDiskSize      types.Int64  `tfsdk:"system_disk_size"`
+	BlockDeviceMappings types.List   `tfsdk:"block_device_mappings"`
+
+	MinCount         types.Int64  `tfsdk:"min_count"`
+	InstanceName     types.String `tfsdk:"instance_name"`
+	SecurityGroupIDs types.List   `tfsdk:"security_group_ids"`
+	KeyName          types.String `tfsdk:"key_name"`
+	UserData         types.String `tfsdk:"user_data"`
+	Tags             types.Map    `tfsdk:"tags"`
+
+	ID               types.String `tfsdk:"id"`
+	PrivateIP        types.String `tfsdk:"private_ip"`
+	PublicIP         types.String `tfsdk:"public_ip"`
+	State            types.String `tfsdk:"state"`
+	AvailabilityZone types.String `tfsdk:"availability_zone"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// instanceSchemaV0 返回版本 0 的资源架构，仅用于读取旧状态
+func instanceSchemaV0(ctx context.Context) *schema.Schema {
+	return &schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"image_id":         schema.StringAttribute{Required: true},
+			"instance_type":    schema.StringAttribute{Required: true},
+			"subnet_id":        schema.StringAttribute{Required: true},
+			"password":         schema.StringAttribute{Required: true, Sensitive: true},
+			"system_disk_size": schema.Int64Attribute{Optional: true},
+			"block_device_mappings": schema.ListNestedAttribute{
+				Optional: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"volume_size": schema.Int64Attribute{Required: true},
+						"volume_type": schema.StringAttribute{Required: true},
+						"device_name": schema.StringAttribute{Optional: true, Computed: true},
+					},
+				},
+			},
+			"min_count":          schema.Int64Attribute{Optional: true, Computed: true},
+			"instance_name":      schema.StringAttribute{Optional: true, Computed: true},
+			"security_group_ids": schema.ListAttribute{ElementType: types.StringType, Optional: true, Computed: true},
+			"key_name":           schema.StringAttribute{Optional: true},
+			"user_data":          schema.StringAttribute{Optional: true},
+			"tags":               schema.MapAttribute{ElementType: types.StringType, Optional: true},
+			"id":                 schema.StringAttribute{Computed: true},
+			"private_ip":         schema.StringAttribute{Computed: true},
+			"public_ip":          schema.StringAttribute{Computed: true},
+			"state":              schema.StringAttribute{Computed: true},
+			"availability_zone":  schema.StringAttribute{Computed: true},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// UpgradeState 定义状态升级器
+func (r *InstanceResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema:   instanceSchemaV0(ctx),
+			StateUpgrader: upgradeInstanceStateV0toV1,
+		},
+	}
+}
+
+// upgradeInstanceStateV0toV1 将旧状态中的 system_disk_size 迁移为 /dev/vda 上的第一个块设备映射
+// 旧状态中没有磁盘类型，迁移后 volume_type 为空，下次 apply 时会沿用配置中的值而不替换实例
+func upgradeInstanceStateV0toV1(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var prior instanceResourceModelV0
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	upgraded := InstanceResourceModel{
+		ImageId:             prior.ImageId,
+		InstanceType:        prior.InstanceType,
+		SubnetID:            prior.SubnetID,
+		Password:            types.StringNull(), // 密码改为只写属性，不再保存到状态中
+		BlockDeviceMappings: prior.BlockDeviceMappings,
+		SystemDiskSize:      types.Int64Null(),
+		MinCount:            prior.MinCount,
+		InstanceName:        prior.InstanceName,
+		SecurityGroupIDs:    prior.SecurityGroupIDs,
+		KeyName:             prior.KeyName,
+		UserData:            prior.UserData,
+		Tags:                prior.Tags,
+		ID:                  prior.ID,
+		TagsAll:             types.MapNull(types.StringType),
+		PrivateIP:           prior.PrivateIP,
+		PublicIP:            prior.PublicIP,
+		State:               prior.State,
+		AvailabilityZone:    prior.AvailabilityZone,
+		Timeouts:            prior.Timeouts,
+	}
+
+	if len(prior.BlockDeviceMappings.Elements()) == 0 && !prior.SystemDiskSize.IsNull() {
+		tflog.Debug(ctx, "迁移 system_disk_size 到 block_device_mappings", map[string]interface{}{
+			"instance_id":      prior.ID.ValueString(),
+			"system_disk_size": prior.SystemDiskSize.ValueInt64(),
+		})
+
+		bdmList, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: blockDeviceMappingAttrTypes}, []BlockDeviceMappingModel{
+			{
+				VolumeSize: prior.SystemDiskSize,
+				VolumeType: types.StringNull(),
+				DeviceName: types.StringValue("/dev/vda"),
+			},
+		})
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		upgraded.BlockDeviceMappings = bdmList
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, upgraded)...)
+}
@@ -0,0 +1,133 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package ec2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// TestUpgradeInstanceStateV0toV1 测试版本 0 的状态升级：system_disk_size 迁移到 /dev/vda，密码从状态中清除
+func TestUpgradeInstanceStateV0toV1(t *testing.T) {
+	ctx := context.Background()
+
+	bdmType := types.ObjectType{AttrTypes: blockDeviceMappingAttrTypes}
+	timeoutsValue := timeouts.Value{Object: types.ObjectNull(map[string]attr.Type{
+		"create": types.StringType,
+		"update": types.StringType,
+		"delete": types.StringType,
+	})}
+
+	existingBDM, diags := types.ListValueFrom(ctx, bdmType, []BlockDeviceMappingModel{
+		{VolumeSize: types.Int64Value(40), VolumeType: types.StringValue("ssd"), DeviceName: types.StringValue("/dev/vda")},
+		{VolumeSize: types.Int64Value(100), VolumeType: types.StringValue("standard"), DeviceName: types.StringValue("/dev/vdb")},
+	})
+	if diags.HasError() {
+		t.Fatalf("构建块设备映射失败: %v", diags)
+	}
+
+	tests := []struct {
+		name           string
+		systemDiskSize types.Int64
+		bdm            types.List
+		wantVda        BlockDeviceMappingModel
+		wantCount      int
+	}{
+		{
+			name:           "system_disk_size",
+			systemDiskSize: types.Int64Value(50),
+			bdm:            types.ListNull(bdmType),
+			wantVda:        BlockDeviceMappingModel{VolumeSize: types.Int64Value(50), VolumeType: types.StringNull(), DeviceName: types.StringValue("/dev/vda")},
+			wantCount:      1,
+		},
+		{
+			name:           "block_device_mappings",
+			systemDiskSize: types.Int64Null(),
+			bdm:            existingBDM,
+			wantVda:        BlockDeviceMappingModel{VolumeSize: types.Int64Value(40), VolumeType: types.StringValue("ssd"), DeviceName: types.StringValue("/dev/vda")},
+			wantCount:      2,
+		},
+	}
+
+	r := &InstanceResource{}
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	upgrader := r.UpgradeState(ctx)[0]
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prior := tfsdk.State{
+				Schema: *upgrader.PriorSchema,
+				Raw:    tftypes.NewValue(upgrader.PriorSchema.Type().TerraformType(ctx), nil),
+			}
+			diags := prior.Set(ctx, instanceResourceModelV0{
+				ImageId:             types.StringValue("ami-12345678"),
+				InstanceType:        types.StringValue("m1.small"),
+				SubnetID:            types.StringValue("subnet-12345678"),
+				Password:            types.StringValue("Test@123456"),
+				SystemDiskSize:      tt.systemDiskSize,
+				BlockDeviceMappings: tt.bdm,
+				MinCount:            types.Int64Value(1),
+				InstanceName:        types.StringValue("test"),
+				SecurityGroupIDs:    types.ListNull(types.StringType),
+				KeyName:             types.StringNull(),
+				UserData:            types.StringNull(),
+				Tags:                types.MapNull(types.StringType),
+				ID:                  types.StringValue("i-12345678"),
+				PrivateIP:           types.StringValue("10.0.0.10"),
+				PublicIP:            types.StringNull(),
+				State:               types.StringValue("running"),
+				AvailabilityZone:    types.StringValue("cn-az1"),
+				Timeouts:            timeoutsValue,
+			})
+			if diags.HasError() {
+				t.Fatalf("构建旧状态失败: %v", diags)
+			}
+
+			resp := resource.UpgradeStateResponse{
+				State: tfsdk.State{
+					Schema: schemaResp.Schema,
+					Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+				},
+			}
+			upgrader.StateUpgrader(ctx, resource.UpgradeStateRequest{State: &prior}, &resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("升级状态失败: %v", resp.Diagnostics)
+			}
+
+			var upgraded InstanceResourceModel
+			if diags := resp.State.Get(ctx, &upgraded); diags.HasError() {
+				t.Fatalf("读取升级后的状态失败: %v", diags)
+			}
+
+			if !upgraded.Password.IsNull() {
+				t.Errorf("password 应为 null，得到 %s", upgraded.Password)
+			}
+			if !upgraded.SystemDiskSize.IsNull() {
+				t.Errorf("system_disk_size 应为 null，得到 %s", upgraded.SystemDiskSize)
+			}
+			if upgraded.ID.ValueString() != "i-12345678" {
+				t.Errorf("id 应保持不变，得到 %s", upgraded.ID)
+			}
+
+			var bdms []BlockDeviceMappingModel
+			if diags := upgraded.BlockDeviceMappings.ElementsAs(ctx, &bdms, false); diags.HasError() {
+				t.Fatalf("读取块设备映射失败: %v", diags)
+			}
+			if len(bdms) != tt.wantCount {
+				t.Fatalf("块设备映射数量应为 %d，得到 %d", tt.wantCount, len(bdms))
+			}
+			if bdms[0] != tt.wantVda {
+				t.Errorf("/dev/vda 应为 %+v，得到 %+v", tt.wantVda, bdms[0])
+			}
+		})
+	}
+}
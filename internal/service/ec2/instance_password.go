@@ -0,0 +1,48 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package ec2
+
+import (
+	"context"
+
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/request"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/service/ec2"
+)
+
+// opResetInstancePassword BingoCloud 扩展的重置实例密码接口，EC2 API 中没有对应的操作
+const opResetInstancePassword = "ResetInstancePassword"
+
+// resetInstancePasswordInput 重置实例密码接口的请求参数
+type resetInstancePasswordInput struct {
+	_ struct{} `type:"structure"`
+
+	InstanceId *string `type:"string" required:"true"`
+	Password   *string `type:"string" required:"true" sensitive:"true"`
+}
+
+// resetInstancePasswordOutput 重置实例密码接口的响应
+type resetInstancePasswordOutput struct {
+	_ struct{} `type:"structure"`
+
+	Return *bool `locationName:"return" type:"boolean"`
+}
+
+// resetInstancePassword 通过 BingoCloud 密码接口原地重置实例的登录密码
+// SDK 中没有该操作，使用 EC2 客户端的通用请求发送，签名和重试与其它 EC2 接口一致
+func resetInstancePassword(ctx context.Context, conn *ec2.EC2, id, password string) error {
+	op := &request.Operation{
+		Name:       opResetInstancePassword,
+		HTTPMethod: "POST",
+		HTTPPath:   "/",
+	}
+
+	req := conn.NewRequest(op, &resetInstancePasswordInput{
+		InstanceId: aws.String(id),
+		Password:   aws.String(password),
+	}, &resetInstancePasswordOutput{})
+	req.SetContext(ctx)
+
+	return req.Send()
+}
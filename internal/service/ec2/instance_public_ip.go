@@ -0,0 +1,56 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package ec2
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/service/ec2"
+)
+
+// flattenInstancePublicIP 返回实例的公网 IP。公网 IP 为绑定的弹性 IP 时保持 prior 不变：
+// 弹性 IP 的绑定由 bingocloud_eip_association 管理，不作为实例在 Terraform 之外的变更
+// 公网 IP 与 prior 相同时不需要查询弹性 IP
+func flattenInstancePublicIP(ctx context.Context, conn *ec2.EC2, instance *ec2.Instance, prior types.String) types.String {
+	publicIP := aws.StringValue(instance.PublicIpAddress)
+	if publicIP == "" || publicIP == prior.ValueString() {
+		return types.StringPointerValue(instance.PublicIpAddress)
+	}
+
+	address, err := findAddressByPublicIP(ctx, conn, publicIP)
+	if err != nil {
+		if !isErrorCode(err, errCodeInvalidAddressNotFound) {
+			tflog.Debug(ctx, "无法判断实例公网 IP 是否为弹性 IP", map[string]interface{}{
+				"instance_id": aws.StringValue(instance.InstanceId),
+				"error":       err.Error(),
+			})
+		}
+		address = nil
+	}
+
+	return instancePublicIPValue(instance, address, prior)
+}
+
+// instancePublicIPValue 根据实例信息和按公网 IP 查询到的弹性 IP 计算 public_ip，address 为 nil 表示不是弹性 IP
+// 公网 IP 是绑定到该实例的弹性 IP 时保持 prior 不变，prior 未知（创建或更新时）则为空
+func instancePublicIPValue(instance *ec2.Instance, address *ec2.Address, prior types.String) types.String {
+	publicIP := aws.StringValue(instance.PublicIpAddress)
+	if publicIP == "" {
+		return types.StringPointerValue(instance.PublicIpAddress)
+	}
+
+	if address == nil ||
+		aws.StringValue(address.PublicIp) != publicIP ||
+		aws.StringValue(address.InstanceId) != aws.StringValue(instance.InstanceId) {
+		return types.StringValue(publicIP)
+	}
+
+	if prior.IsUnknown() {
+		return types.StringNull()
+	}
+	return prior
+}
@@ -0,0 +1,96 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package ec2
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/service/ec2"
+)
+
+func TestInstancePublicIPValue(t *testing.T) {
+	instance := &ec2.Instance{
+		InstanceId:      aws.String("i-12345678"),
+		PublicIpAddress: aws.String("203.0.113.10"),
+	}
+	boundAddress := &ec2.Address{
+		PublicIp:   aws.String("203.0.113.10"),
+		InstanceId: aws.String("i-12345678"),
+	}
+
+	tests := []struct {
+		name     string
+		instance *ec2.Instance
+		address  *ec2.Address
+		prior    types.String
+		want     types.String
+	}{
+		{
+			name:     "no_public_ip",
+			instance: &ec2.Instance{InstanceId: aws.String("i-12345678")},
+			prior:    types.StringValue("198.51.100.5"),
+			want:     types.StringNull(),
+		},
+		{
+			name:     "auto_assigned",
+			instance: instance,
+			prior:    types.StringUnknown(),
+			want:     types.StringValue("203.0.113.10"),
+		},
+		{
+			name:     "auto_assigned_changed",
+			instance: instance,
+			prior:    types.StringValue("198.51.100.5"),
+			want:     types.StringValue("203.0.113.10"),
+		},
+		{
+			name:     "eip_keeps_prior",
+			instance: instance,
+			address:  boundAddress,
+			prior:    types.StringValue("198.51.100.5"),
+			want:     types.StringValue("198.51.100.5"),
+		},
+		{
+			name:     "eip_prior_null",
+			instance: instance,
+			address:  boundAddress,
+			prior:    types.StringNull(),
+			want:     types.StringNull(),
+		},
+		{
+			name:     "eip_prior_unknown",
+			instance: instance,
+			address:  boundAddress,
+			prior:    types.StringUnknown(),
+			want:     types.StringNull(),
+		},
+		{
+			name:     "eip_bound_to_other_instance",
+			instance: instance,
+			address: &ec2.Address{
+				PublicIp:   aws.String("203.0.113.10"),
+				InstanceId: aws.String("i-87654321"),
+			},
+			prior: types.StringValue("198.51.100.5"),
+			want:  types.StringValue("203.0.113.10"),
+		},
+		{
+			name:     "eip_not_associated",
+			instance: instance,
+			address:  &ec2.Address{PublicIp: aws.String("203.0.113.10")},
+			prior:    types.StringValue("198.51.100.5"),
+			want:     types.StringValue("203.0.113.10"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := instancePublicIPValue(tt.instance, tt.address, tt.prior); !got.Equal(tt.want) {
+				t.Errorf("public_ip 应为 %s，得到 %s", tt.want, got)
+			}
+		})
+	}
+}
@@ -1,27 +1,36 @@
 // Copyright IBM Corp. 2021, 2025
 // SPDX-License-Identifier: MPL-2.0
 
-package ec2
+package ec2_test
 
 import (
 	"fmt"
 	"os"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/mulei1288/terraform-provider-bingocloud/internal/acctest"
 )
 
 // testAccInstanceConfig 生成虚拟机资源的测试配置
 func testAccInstanceConfig(name string) string {
-	return providerConfig() + fmt.Sprintf(`
+	return acctest.ProviderConfig() + fmt.Sprintf(`
 resource "bingocloud_instance" "test" {
   image_id         = %[1]q
   instance_type    = "m1.small"
   subnet_id        = %[2]q
   password         = "Test@123456"
-  system_disk_size = 20
   instance_name    = %[3]q
 
+  block_device_mappings = [
+    {
+      volume_size = 20
+      volume_type = "standard"
+    }
+  ]
+
   tags = {
     Environment = "test"
     ManagedBy   = "terraform"
@@ -33,8 +42,8 @@ resource "bingocloud_instance" "test" {
 // TestAccInstanceResource_basic 测试虚拟机资源的基础 CRUD 操作
 func TestAccInstanceResource_basic(t *testing.T) {
 	resource.Test(t, resource.TestCase{
-		PreCheck:                 func() { testAccPreCheck(t) },
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			// 创建和读取测试
 			{
@@ -42,7 +51,8 @@ func TestAccInstanceResource_basic(t *testing.T) {
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("bingocloud_instance.test", "instance_name", "test-instance"),
 					resource.TestCheckResourceAttr("bingocloud_instance.test", "instance_type", "m1.small"),
-					resource.TestCheckResourceAttr("bingocloud_instance.test", "system_disk_size", "20"),
+					resource.TestCheckResourceAttr("bingocloud_instance.test", "block_device_mappings.0.volume_size", "20"),
+					resource.TestCheckResourceAttr("bingocloud_instance.test", "block_device_mappings.0.device_name", "/dev/vda"),
 					resource.TestCheckResourceAttrSet("bingocloud_instance.test", "id"),
 					resource.TestCheckResourceAttrSet("bingocloud_instance.test", "private_ip"),
 					resource.TestCheckResourceAttr("bingocloud_instance.test", "tags.Environment", "test"),
@@ -54,8 +64,8 @@ func TestAccInstanceResource_basic(t *testing.T) {
 				ResourceName:      "bingocloud_instance.test",
 				ImportState:       true,
 				ImportStateVerify: true,
-				// 密码、系统盘大小、实例名称和标签字段不会被导入，因此需要忽略
-				ImportStateVerifyIgnore: []string{"password", "system_disk_size", "instance_name", "tags"},
+				// 密码不会被导入，因此需要忽略
+				ImportStateVerifyIgnore: []string{"password"},
 			},
 		},
 	})
@@ -63,15 +73,21 @@ func TestAccInstanceResource_basic(t *testing.T) {
 
 // testAccInstanceConfigUpdate 生成更新后的虚拟机资源配置
 func testAccInstanceConfigUpdate(name string) string {
-	return providerConfig() + fmt.Sprintf(`
+	return acctest.ProviderConfig() + fmt.Sprintf(`
 resource "bingocloud_instance" "test" {
   image_id         = %[1]q
   instance_type    = "m1.medium"
   subnet_id        = %[2]q
   password         = "Test@123456"
-  system_disk_size = 20
   instance_name    = %[3]q
 
+  block_device_mappings = [
+    {
+      volume_size = 20
+      volume_type = "standard"
+    }
+  ]
+
   tags = {
     Environment = "production"
     ManagedBy   = "terraform"
@@ -84,8 +100,8 @@ resource "bingocloud_instance" "test" {
 // TestAccInstanceResource_update 测试虚拟机资源的更新操作
 func TestAccInstanceResource_update(t *testing.T) {
 	resource.Test(t, resource.TestCase{
-		PreCheck:                 func() { testAccPreCheck(t) },
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			// 创建初始资源
 			{
@@ -110,14 +126,20 @@ func TestAccInstanceResource_update(t *testing.T) {
 
 // testAccInstanceConfigWithSecurityGroup 生成带安全组的虚拟机资源配置
 func testAccInstanceConfigWithSecurityGroup(name string) string {
-	return providerConfig() + fmt.Sprintf(`
+	return acctest.ProviderConfig() + fmt.Sprintf(`
 resource "bingocloud_instance" "test" {
   image_id         = %[1]q
   instance_type    = "m1.small"
   subnet_id        = %[2]q
   password         = "Test@123456"
-  system_disk_size = 20
   instance_name    = %[3]q
+
+  block_device_mappings = [
+    {
+      volume_size = 20
+      volume_type = "standard"
+    }
+  ]
   
   security_group_ids = [%[4]q]
 
@@ -132,12 +154,12 @@ resource "bingocloud_instance" "test" {
 func TestAccInstanceResource_withSecurityGroup(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck: func() {
-			testAccPreCheck(t)
+			acctest.PreCheck(t)
 			if v := os.Getenv("BINGOCLOUD_TEST_SECURITY_GROUP"); v == "" {
 				t.Skip("跳过测试：BINGOCLOUD_TEST_SECURITY_GROUP 未设置")
 			}
 		},
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccInstanceConfigWithSecurityGroup("test-instance-sg"),
@@ -149,3 +171,390 @@ func TestAccInstanceResource_withSecurityGroup(t *testing.T) {
 		},
 	})
 }
+
+// testAccInstanceConfigLegacySystemDiskSize 生成仍使用已移除的 system_disk_size 的旧配置
+func testAccInstanceConfigLegacySystemDiskSize(name string) string {
+	return acctest.ProviderConfig() + fmt.Sprintf(`
+resource "bingocloud_instance" "test" {
+  image_id         = %[1]q
+  instance_type    = "m1.small"
+  subnet_id        = %[2]q
+  password         = "Test@123456"
+  system_disk_size = 20
+  instance_name    = %[3]q
+}
+`, os.Getenv("BINGOCLOUD_TEST_AMI"), os.Getenv("BINGOCLOUD_TEST_SUBNET"), name)
+}
+
+// TestAccInstanceResource_legacySystemDiskSize 测试旧配置在 plan 阶段给出改写提示
+func TestAccInstanceResource_legacySystemDiskSize(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccInstanceConfigLegacySystemDiskSize("test-instance-legacy"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`system_disk_size 已移除`),
+			},
+		},
+	})
+}
+
+// testAccInstanceConfigDataDisks 生成带 dataDiskSize 指定大小数据盘的虚拟机资源配置，dataDiskSize 为 0 时不挂载数据盘
+func testAccInstanceConfigDataDisks(name string, dataDiskSize int) string {
+	dataDisk := ""
+	if dataDiskSize > 0 {
+		dataDisk = fmt.Sprintf(`,
+    {
+      volume_size = %d
+      volume_type = "standard"
+      device_name = "/dev/vdb"
+    }`, dataDiskSize)
+	}
+
+	return acctest.ProviderConfig() + fmt.Sprintf(`
+resource "bingocloud_instance" "test" {
+  image_id      = %[1]q
+  instance_type = "m1.small"
+  subnet_id     = %[2]q
+  password      = "Test@123456"
+  instance_name = %[3]q
+
+  block_device_mappings = [
+    {
+      volume_size = 20
+      volume_type = "standard"
+    }%[4]s
+  ]
+}
+`, os.Getenv("BINGOCLOUD_TEST_AMI"), os.Getenv("BINGOCLOUD_TEST_SUBNET"), name, dataDisk)
+}
+
+// TestAccInstanceResource_dataDisks 测试数据盘的原地新增、扩容和删除
+func TestAccInstanceResource_dataDisks(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// 只有系统盘
+			{
+				Config: testAccInstanceConfigDataDisks("test-instance-disks", 0),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("bingocloud_instance.test", "block_device_mappings.#", "1"),
+				),
+			},
+			// 新增数据盘，不替换实例
+			{
+				Config: testAccInstanceConfigDataDisks("test-instance-disks", 10),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("bingocloud_instance.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("bingocloud_instance.test", "block_device_mappings.#", "2"),
+					resource.TestCheckResourceAttr("bingocloud_instance.test", "block_device_mappings.1.volume_size", "10"),
+				),
+			},
+			// 扩容数据盘
+			{
+				Config: testAccInstanceConfigDataDisks("test-instance-disks", 20),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("bingocloud_instance.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("bingocloud_instance.test", "block_device_mappings.1.volume_size", "20"),
+				),
+			},
+			// 删除数据盘
+			{
+				Config: testAccInstanceConfigDataDisks("test-instance-disks", 0),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("bingocloud_instance.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("bingocloud_instance.test", "block_device_mappings.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+// testAccInstanceConfigInstanceState 生成指定期望电源状态的虚拟机资源配置
+func testAccInstanceConfigInstanceState(name, instanceState string) string {
+	return acctest.ProviderConfig() + fmt.Sprintf(`
+resource "bingocloud_instance" "test" {
+  image_id       = %[1]q
+  instance_type  = "m1.small"
+  subnet_id      = %[2]q
+  password       = "Test@123456"
+  instance_name  = %[3]q
+  instance_state = %[4]q
+
+  block_device_mappings = [
+    {
+      volume_size = 20
+      volume_type = "standard"
+    }
+  ]
+}
+`, os.Getenv("BINGOCLOUD_TEST_AMI"), os.Getenv("BINGOCLOUD_TEST_SUBNET"), name, instanceState)
+}
+
+// TestAccInstanceResource_instanceState 测试实例的停止和启动
+func TestAccInstanceResource_instanceState(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccInstanceConfigInstanceState("test-instance-state", "stopped"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("bingocloud_instance.test", "instance_state", "stopped"),
+					resource.TestCheckResourceAttr("bingocloud_instance.test", "state", "stopped"),
+				),
+			},
+			{
+				Config: testAccInstanceConfigInstanceState("test-instance-state", "running"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("bingocloud_instance.test", "instance_state", "running"),
+					resource.TestCheckResourceAttr("bingocloud_instance.test", "state", "running"),
+				),
+			},
+		},
+	})
+}
+
+// testAccInstanceConfigProtection 生成指定终止保护和停止保护的虚拟机资源配置
+func testAccInstanceConfigProtection(name, instanceState string, disableApiTermination, disableApiStop bool) string {
+	return acctest.ProviderConfig() + fmt.Sprintf(`
+resource "bingocloud_instance" "test" {
+  image_id       = %[1]q
+  instance_type  = "m1.small"
+  subnet_id      = %[2]q
+  password       = "Test@123456"
+  instance_name  = %[3]q
+  instance_state = %[4]q
+
+  disable_api_termination = %[5]t
+  disable_api_stop        = %[6]t
+
+  block_device_mappings = [
+    {
+      volume_size = 20
+      volume_type = "standard"
+    }
+  ]
+}
+`, os.Getenv("BINGOCLOUD_TEST_AMI"), os.Getenv("BINGOCLOUD_TEST_SUBNET"), name, instanceState, disableApiTermination, disableApiStop)
+}
+
+// TestAccInstanceResource_protection 测试终止保护和停止保护的开启与原地关闭
+func TestAccInstanceResource_protection(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccInstanceConfigProtection("test-instance-protection", "running", true, true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("bingocloud_instance.test", "disable_api_termination", "true"),
+					resource.TestCheckResourceAttr("bingocloud_instance.test", "disable_api_stop", "true"),
+				),
+			},
+			// 停止保护开启时不允许停止实例
+			{
+				Config:      testAccInstanceConfigProtection("test-instance-protection", "stopped", true, true),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("实例已开启停止保护"),
+			},
+			// 原地关闭保护，之后才能销毁
+			{
+				Config: testAccInstanceConfigProtection("test-instance-protection", "running", false, false),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("bingocloud_instance.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("bingocloud_instance.test", "disable_api_termination", "false"),
+					resource.TestCheckResourceAttr("bingocloud_instance.test", "disable_api_stop", "false"),
+				),
+			},
+		},
+	})
+}
+
+// testAccInstanceConfigPasswordVersion 生成指定密码和密码版本号的虚拟机资源配置
+func testAccInstanceConfigPasswordVersion(name, password string, passwordVersion int) string {
+	return acctest.ProviderConfig() + fmt.Sprintf(`
+resource "bingocloud_instance" "test" {
+  image_id         = %[1]q
+  instance_type    = "m1.small"
+  subnet_id        = %[2]q
+  password         = %[4]q
+  password_version = %[5]d
+  instance_name    = %[3]q
+
+  block_device_mappings = [
+    {
+      volume_size = 20
+      volume_type = "standard"
+    }
+  ]
+}
+`, os.Getenv("BINGOCLOUD_TEST_AMI"), os.Getenv("BINGOCLOUD_TEST_SUBNET"), name, password, passwordVersion)
+}
+
+// TestAccInstanceResource_passwordVersion 测试密码不保存到状态中，修改密码版本号时原地重置密码
+func TestAccInstanceResource_passwordVersion(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccInstanceConfigPasswordVersion("test-instance-password", "Test@123456", 1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("bingocloud_instance.test", "password"),
+					resource.TestCheckResourceAttr("bingocloud_instance.test", "password_version", "1"),
+				),
+			},
+			// 只修改密码不会产生变更
+			{
+				Config:   testAccInstanceConfigPasswordVersion("test-instance-password", "Test@654321", 1),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccInstanceConfigPasswordVersion("test-instance-password", "Test@654321", 2),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("bingocloud_instance.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("bingocloud_instance.test", "password"),
+					resource.TestCheckResourceAttr("bingocloud_instance.test", "password_version", "2"),
+				),
+			},
+		},
+	})
+}
+
+// testAccInstanceConfigDefaultTags 生成使用 provider 默认标签的虚拟机资源配置
+func testAccInstanceConfigDefaultTags(name, defaultValue string) string {
+	return acctest.ProviderConfigDefaultTags("CostCenter", defaultValue, "platform:") + fmt.Sprintf(`
+resource "bingocloud_instance" "test" {
+  image_id      = %[1]q
+  instance_type = "m1.small"
+  subnet_id     = %[2]q
+  password      = "Test@123456"
+  instance_name = %[3]q
+
+  block_device_mappings = [
+    {
+      volume_size = 20
+      volume_type = "standard"
+    }
+  ]
+
+  tags = {
+    Environment = "test"
+  }
+}
+`, os.Getenv("BINGOCLOUD_TEST_AMI"), os.Getenv("BINGOCLOUD_TEST_SUBNET"), name)
+}
+
+// TestAccInstanceResource_defaultTags 测试 provider 默认标签合并到 tags_all，且不出现在 tags 中
+func TestAccInstanceResource_defaultTags(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccInstanceConfigDefaultTags("test-instance-default-tags", "cc-001"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("bingocloud_instance.test", "tags.%", "1"),
+					resource.TestCheckResourceAttr("bingocloud_instance.test", "tags.Environment", "test"),
+					resource.TestCheckResourceAttr("bingocloud_instance.test", "tags_all.%", "2"),
+					resource.TestCheckResourceAttr("bingocloud_instance.test", "tags_all.CostCenter", "cc-001"),
+				),
+			},
+			// 修改默认标签时原地更新实例标签
+			{
+				Config: testAccInstanceConfigDefaultTags("test-instance-default-tags", "cc-002"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("bingocloud_instance.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("bingocloud_instance.test", "tags.%", "1"),
+					resource.TestCheckResourceAttr("bingocloud_instance.test", "tags_all.CostCenter", "cc-002"),
+				),
+			},
+		},
+	})
+}
+
+// testAccInstanceConfigNameTag 生成在 tags 中配置 Name 的虚拟机资源配置
+func testAccInstanceConfigNameTag(name string) string {
+	return acctest.ProviderConfig() + fmt.Sprintf(`
+resource "bingocloud_instance" "test" {
+  image_id      = %[1]q
+  instance_type = "m1.small"
+  subnet_id     = %[2]q
+  password      = "Test@123456"
+  instance_name = %[3]q
+
+  block_device_mappings = [
+    {
+      volume_size = 20
+      volume_type = "standard"
+    }
+  ]
+
+  tags = {
+    Name = %[3]q
+  }
+}
+`, os.Getenv("BINGOCLOUD_TEST_AMI"), os.Getenv("BINGOCLOUD_TEST_SUBNET"), name)
+}
+
+// TestAccInstanceResource_rename 测试通过 instance_name 原地修改 Name 标签，tags 中配置 Name 时在计划阶段报错
+func TestAccInstanceResource_rename(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccInstanceConfigNameTag("test-instance-rename"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Name 标签与 instance_name 冲突"),
+			},
+			{
+				Config: testAccInstanceConfig("test-instance-rename"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("bingocloud_instance.test", "instance_name", "test-instance-rename"),
+					resource.TestCheckNoResourceAttr("bingocloud_instance.test", "tags.Name"),
+					resource.TestCheckNoResourceAttr("bingocloud_instance.test", "tags_all.Name"),
+				),
+			},
+			{
+				Config: testAccInstanceConfig("test-instance-renamed"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("bingocloud_instance.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("bingocloud_instance.test", "instance_name", "test-instance-renamed"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,393 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package ec2
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/service/ec2"
+)
+
+// volumeCleanupTimeout 清理创建失败的卷的超时时间
+const volumeCleanupTimeout = 10 * time.Minute
+
+// matchDataDisks 将计划中的数据盘与状态中的数据盘一一对应
+// 指定了设备名称的数据盘按设备名称匹配，其余按顺序匹配剩下的数据盘；
+// 返回计划索引到状态索引的映射，以及状态中未被匹配（需要删除）的索引
+func matchDataDisks(stateDisks, planDisks []BlockDeviceMappingModel) (map[int]int, []int) {
+	matches := make(map[int]int)
+	used := make(map[int]bool)
+
+	// 按设备名称匹配
+	for pi, p := range planDisks {
+		if p.DeviceName.IsUnknown() || p.DeviceName.IsNull() {
+			continue
+		}
+		for si, s := range stateDisks {
+			if !used[si] && s.DeviceName.Equal(p.DeviceName) {
+				matches[pi] = si
+				used[si] = true
+				break
+			}
+		}
+	}
+
+	// 未指定设备名称的数据盘按顺序匹配
+	next := 0
+	for pi, p := range planDisks {
+		if !p.DeviceName.IsUnknown() && !p.DeviceName.IsNull() {
+			continue
+		}
+		for next < len(stateDisks) && used[next] {
+			next++
+		}
+		if next >= len(stateDisks) {
+			break
+		}
+		matches[pi] = next
+		used[next] = true
+	}
+
+	var removed []int
+	for si := range stateDisks {
+		if !used[si] {
+			removed = append(removed, si)
+		}
+	}
+
+	return matches, removed
+}
+
+// updateDataDisks 原地调整实例的数据盘（block_device_mappings 中第一个元素之后的磁盘）
+// 移除的数据盘先卸载再删除，保留的数据盘按需扩容或修改类型，新增的数据盘创建后挂载；
+// 成功时返回补全设备名称后的完整块设备映射列表，失败时返回已经完成的变更所对应的列表，用于保存到状态中
+func updateDataDisks(ctx context.Context, conn *ec2.EC2, instance *ec2.Instance, stateList, planList []BlockDeviceMappingModel, timeout time.Duration) ([]BlockDeviceMappingModel, error) {
+	instanceID := aws.StringValue(instance.InstanceId)
+
+	// applied 记录已经完成的变更，以状态中的列表为基准
+	applied := make([]BlockDeviceMappingModel, len(stateList))
+	copy(applied, stateList)
+
+	result := make([]BlockDeviceMappingModel, len(planList))
+	copy(result, planList)
+
+	// 系统盘的变更已由计划修饰器处理为替换，这里只补全设备名称
+	if len(result) > 0 && len(stateList) > 0 && result[0].DeviceName.IsUnknown() {
+		result[0].DeviceName = stateList[0].DeviceName
+	}
+
+	var stateDisks, planDisks []BlockDeviceMappingModel
+	if len(stateList) > 1 {
+		stateDisks = stateList[1:]
+	}
+	if len(planList) > 1 {
+		planDisks = planList[1:]
+	}
+
+	// 设备名称到卷 ID 的映射
+	volumeIDs := make(map[string]string)
+	for _, bdm := range instance.BlockDeviceMappings {
+		if bdm.Ebs != nil {
+			volumeIDs[aws.StringValue(bdm.DeviceName)] = aws.StringValue(bdm.Ebs.VolumeId)
+		}
+	}
+
+	matches, removed := matchDataDisks(stateDisks, planDisks)
+
+	// 删除移除的数据盘
+	for _, si := range removed {
+		deviceName := stateDisks[si].DeviceName.ValueString()
+		volumeID, ok := volumeIDs[deviceName]
+		if !ok {
+			applied = removeDataDisk(applied, deviceName)
+			continue
+		}
+
+		tflog.Debug(ctx, "卸载并删除数据盘", map[string]interface{}{
+			"instance_id": instanceID,
+			"device_name": deviceName,
+			"volume_id":   volumeID,
+		})
+
+		if err := detachAndDeleteVolume(ctx, conn, instanceID, volumeID, timeout); err != nil {
+			return applied, err
+		}
+		delete(volumeIDs, deviceName)
+		applied = removeDataDisk(applied, deviceName)
+	}
+
+	// 修改保留的数据盘
+	for pi, si := range matches {
+		p, s := planDisks[pi], stateDisks[si]
+		result[pi+1].DeviceName = s.DeviceName
+
+		volumeID, ok := volumeIDs[s.DeviceName.ValueString()]
+		if !ok {
+			return applied, fmt.Errorf("实例 %s 上找不到设备 %s 对应的卷，请先执行 terraform refresh", instanceID, s.DeviceName.ValueString())
+		}
+
+		input := &ec2.ModifyVolumeInput{VolumeId: aws.String(volumeID)}
+		modified := false
+		if !p.VolumeSize.Equal(s.VolumeSize) {
+			input.Size = aws.Int64(p.VolumeSize.ValueInt64())
+			modified = true
+		}
+		if !s.VolumeType.IsNull() && !p.VolumeType.Equal(s.VolumeType) {
+			input.VolumeType = aws.String(p.VolumeType.ValueString())
+			modified = true
+		}
+		if !modified {
+			continue
+		}
+
+		tflog.Debug(ctx, "修改数据盘", map[string]interface{}{
+			"instance_id": instanceID,
+			"volume_id":   volumeID,
+			"volume_size": p.VolumeSize.ValueInt64(),
+			"volume_type": p.VolumeType.ValueString(),
+		})
+
+		if _, err := conn.ModifyVolumeWithContext(ctx, input); err != nil {
+			return applied, fmt.Errorf("修改卷 %s 失败: %w", volumeID, err)
+		}
+		for i := range applied {
+			if i > 0 && applied[i].DeviceName.Equal(s.DeviceName) {
+				applied[i].VolumeSize = p.VolumeSize
+				applied[i].VolumeType = p.VolumeType
+			}
+		}
+	}
+
+	// 创建并挂载新增的数据盘
+	for pi, p := range planDisks {
+		if _, ok := matches[pi]; ok {
+			continue
+		}
+
+		deviceName := p.DeviceName.ValueString()
+		if p.DeviceName.IsUnknown() || p.DeviceName.IsNull() {
+			deviceName = nextDeviceName(volumeIDs, result)
+		}
+
+		tflog.Debug(ctx, "创建并挂载数据盘", map[string]interface{}{
+			"instance_id": instanceID,
+			"device_name": deviceName,
+			"volume_size": p.VolumeSize.ValueInt64(),
+		})
+
+		volumeID, err := createAndAttachVolume(ctx, conn, instance, deviceName, p, timeout)
+		if err != nil {
+			return applied, err
+		}
+		volumeIDs[deviceName] = volumeID
+		result[pi+1].DeviceName = types.StringValue(deviceName)
+		applied = append(applied, result[pi+1])
+	}
+
+	return result, nil
+}
+
+// removeDataDisk 从块设备映射列表中移除指定设备名称的数据盘，第一个元素（系统盘）保持不变
+func removeDataDisk(list []BlockDeviceMappingModel, deviceName string) []BlockDeviceMappingModel {
+	result := make([]BlockDeviceMappingModel, 0, len(list))
+	for i, bdm := range list {
+		if i > 0 && bdm.DeviceName.ValueString() == deviceName {
+			continue
+		}
+		result = append(result, bdm)
+	}
+	return result
+}
+
+// nextDeviceName 返回第一个未被占用的 /dev/vdX 设备名称
+func nextDeviceName(volumeIDs map[string]string, planned []BlockDeviceMappingModel) string {
+	used := make(map[string]bool, len(volumeIDs)+len(planned))
+	for deviceName := range volumeIDs {
+		used[deviceName] = true
+	}
+	for _, bdm := range planned {
+		if !bdm.DeviceName.IsUnknown() && !bdm.DeviceName.IsNull() {
+			used[bdm.DeviceName.ValueString()] = true
+		}
+	}
+
+	for c := 'b'; c <= 'z'; c++ {
+		deviceName := "/dev/vd" + string(c)
+		if !used[deviceName] {
+			return deviceName
+		}
+	}
+	return ""
+}
+
+// createAndAttachVolume 在实例所在可用区创建卷并挂载到实例，实例终止时随之删除
+// 创建卷之后的任一步骤失败时删除该卷（已挂载的先卸载），避免留下不在状态中的卷
+func createAndAttachVolume(ctx context.Context, conn *ec2.EC2, instance *ec2.Instance, deviceName string, disk BlockDeviceMappingModel, timeout time.Duration) (volumeID string, err error) {
+	if deviceName == "" {
+		return "", fmt.Errorf("实例 %s 没有可用的设备名称", aws.StringValue(instance.InstanceId))
+	}
+
+	volume, err := conn.CreateVolumeWithContext(ctx, &ec2.CreateVolumeInput{
+		AvailabilityZone: instance.Placement.AvailabilityZone,
+		Size:             aws.Int64(disk.VolumeSize.ValueInt64()),
+		VolumeType:       aws.String(disk.VolumeType.ValueString()),
+	})
+	if err != nil {
+		return "", fmt.Errorf("创建卷失败: %w", err)
+	}
+	volumeID = aws.StringValue(volume.VolumeId)
+
+	attached := false
+	defer func() {
+		if err == nil {
+			return
+		}
+		if cleanupErr := cleanupVolume(ctx, conn, aws.StringValue(instance.InstanceId), volumeID, attached); cleanupErr != nil {
+			err = fmt.Errorf("%w；清理卷 %s 失败，需要手动删除: %v", err, volumeID, cleanupErr)
+		}
+		volumeID = ""
+	}()
+
+	describeInput := &ec2.DescribeVolumesInput{
+		VolumeIds: []*string{aws.String(volumeID)},
+	}
+
+	if err := conn.WaitUntilVolumeAvailableWithContext(ctx, describeInput, waiterOptions(timeout)...); err != nil {
+		return volumeID, fmt.Errorf("等待卷 %s 可用失败: %w", volumeID, err)
+	}
+
+	_, err = conn.AttachVolumeWithContext(ctx, &ec2.AttachVolumeInput{
+		Device:     aws.String(deviceName),
+		InstanceId: instance.InstanceId,
+		VolumeId:   aws.String(volumeID),
+	})
+	if err != nil {
+		return volumeID, fmt.Errorf("挂载卷 %s 失败: %w", volumeID, err)
+	}
+	attached = true
+
+	if err := conn.WaitUntilVolumeInUseWithContext(ctx, describeInput, waiterOptions(timeout)...); err != nil {
+		return volumeID, fmt.Errorf("等待卷 %s 挂载失败: %w", volumeID, err)
+	}
+
+	// 与创建实例时的块设备映射保持一致，实例终止时删除该卷
+	_, err = conn.ModifyInstanceAttributeWithContext(ctx, &ec2.ModifyInstanceAttributeInput{
+		InstanceId: instance.InstanceId,
+		BlockDeviceMappings: []*ec2.InstanceBlockDeviceMappingSpecification{
+			{
+				DeviceName: aws.String(deviceName),
+				Ebs: &ec2.EbsInstanceBlockDeviceSpecification{
+					DeleteOnTermination: aws.Bool(true),
+					VolumeId:            aws.String(volumeID),
+				},
+			},
+		},
+	})
+	if err != nil {
+		return volumeID, fmt.Errorf("设置卷 %s 随实例删除失败: %w", volumeID, err)
+	}
+
+	return volumeID, nil
+}
+
+// cleanupVolume 删除挂载失败的卷，已挂载的先卸载
+// 原 context 可能已超时，使用独立的 context 进行清理
+func cleanupVolume(ctx context.Context, conn *ec2.EC2, instanceID, volumeID string, attached bool) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), volumeCleanupTimeout)
+	defer cancel()
+
+	tflog.Debug(ctx, "删除挂载失败的卷", map[string]interface{}{
+		"instance_id": instanceID,
+		"volume_id":   volumeID,
+		"attached":    attached,
+	})
+
+	if attached {
+		return detachAndDeleteVolume(ctx, conn, instanceID, volumeID, volumeCleanupTimeout)
+	}
+
+	// 卷可能仍在创建中，等待可用后再删除，等待失败时仍尝试删除
+	describeInput := &ec2.DescribeVolumesInput{
+		VolumeIds: []*string{aws.String(volumeID)},
+	}
+	_ = conn.WaitUntilVolumeAvailableWithContext(ctx, describeInput, waiterOptions(volumeCleanupTimeout)...)
+
+	if _, err := conn.DeleteVolumeWithContext(ctx, &ec2.DeleteVolumeInput{VolumeId: aws.String(volumeID)}); err != nil {
+		return fmt.Errorf("删除卷 %s 失败: %w", volumeID, err)
+	}
+	return nil
+}
+
+// detachAndDeleteVolume 从实例卸载卷并删除
+func detachAndDeleteVolume(ctx context.Context, conn *ec2.EC2, instanceID, volumeID string, timeout time.Duration) error {
+	_, err := conn.DetachVolumeWithContext(ctx, &ec2.DetachVolumeInput{
+		InstanceId: aws.String(instanceID),
+		VolumeId:   aws.String(volumeID),
+	})
+	if err != nil {
+		return fmt.Errorf("卸载卷 %s 失败: %w", volumeID, err)
+	}
+
+	describeInput := &ec2.DescribeVolumesInput{
+		VolumeIds: []*string{aws.String(volumeID)},
+	}
+
+	if err := conn.WaitUntilVolumeAvailableWithContext(ctx, describeInput, waiterOptions(timeout)...); err != nil {
+		return fmt.Errorf("等待卷 %s 卸载失败: %w", volumeID, err)
+	}
+
+	if _, err := conn.DeleteVolumeWithContext(ctx, &ec2.DeleteVolumeInput{VolumeId: aws.String(volumeID)}); err != nil {
+		return fmt.Errorf("删除卷 %s 失败: %w", volumeID, err)
+	}
+
+	return nil
+}
+
+// instancePartialState 返回 Update 在调整数据盘时失败需要保存的状态
+// 数据盘之前的修改已经生效，使用计划中的值；数据盘使用 bdm 中已完成的结果；
+// 最后才开启的保护设置和计划中未知的计算属性保持原状态
+func instancePartialState(plan, state InstanceResourceModel, bdm types.List) InstanceResourceModel {
+	partial := plan
+	partial.BlockDeviceMappings = bdm
+
+	if plan.DisableApiTermination.IsUnknown() || plan.DisableApiTermination.ValueBool() {
+		partial.DisableApiTermination = state.DisableApiTermination
+	}
+	if plan.DisableApiStop.IsUnknown() || plan.DisableApiStop.ValueBool() {
+		partial.DisableApiStop = state.DisableApiStop
+	}
+	if plan.MinCount.IsUnknown() {
+		partial.MinCount = state.MinCount
+	}
+	if plan.SecurityGroupIDs.IsUnknown() {
+		partial.SecurityGroupIDs = state.SecurityGroupIDs
+	}
+	if plan.TagsAll.IsUnknown() {
+		partial.TagsAll = state.TagsAll
+	}
+
+	for _, v := range []struct {
+		partial *types.String
+		state   types.String
+	}{
+		{&partial.ID, state.ID},
+		{&partial.InstanceName, state.InstanceName},
+		{&partial.InstanceState, state.InstanceState},
+		{&partial.State, state.State},
+		{&partial.PrivateIP, state.PrivateIP},
+		{&partial.PublicIP, state.PublicIP},
+		{&partial.AvailabilityZone, state.AvailabilityZone},
+	} {
+		if v.partial.IsUnknown() {
+			*v.partial = v.state
+		}
+	}
+
+	return partial
+}
@@ -0,0 +1,71 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package ec2
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// TestInstancePartialState 测试调整数据盘失败时保存的状态：已生效的修改使用计划中的值，未开启的保护和未知的计算属性保持原状态
+func TestInstancePartialState(t *testing.T) {
+	state := InstanceResourceModel{
+		ID:                    types.StringValue("i-12345678"),
+		InstanceType:          types.StringValue("m1.small"),
+		InstanceName:          types.StringValue("old"),
+		InstanceState:         types.StringValue("running"),
+		PasswordVersion:       types.Int64Value(1),
+		SecurityGroupIDs:      types.ListValueMust(types.StringType, nil),
+		TagsAll:               types.MapValueMust(types.StringType, nil),
+		DisableApiTermination: types.BoolValue(false),
+		DisableApiStop:        types.BoolValue(true),
+		State:                 types.StringValue("running"),
+		PrivateIP:             types.StringValue("10.0.0.10"),
+		PublicIP:              types.StringValue("203.0.113.10"),
+		AvailabilityZone:      types.StringValue("cn-1a"),
+		BlockDeviceMappings:   types.ListNull(types.ObjectType{AttrTypes: blockDeviceMappingAttrTypes}),
+	}
+	plan := InstanceResourceModel{
+		ID:                    types.StringValue("i-12345678"),
+		InstanceType:          types.StringValue("m1.large"),
+		InstanceName:          types.StringValue("new"),
+		InstanceState:         types.StringValue("stopped"),
+		PasswordVersion:       types.Int64Value(2),
+		SecurityGroupIDs:      types.ListValueMust(types.StringType, nil),
+		TagsAll:               types.MapUnknown(types.StringType),
+		DisableApiTermination: types.BoolValue(true),
+		DisableApiStop:        types.BoolValue(false),
+		State:                 types.StringUnknown(),
+		PrivateIP:             types.StringUnknown(),
+		PublicIP:              types.StringUnknown(),
+		AvailabilityZone:      types.StringUnknown(),
+		BlockDeviceMappings:   types.ListUnknown(types.ObjectType{AttrTypes: blockDeviceMappingAttrTypes}),
+	}
+	bdm := types.ListValueMust(types.ObjectType{AttrTypes: blockDeviceMappingAttrTypes}, nil)
+
+	got := instancePartialState(plan, state, bdm)
+
+	for _, tt := range []struct {
+		name      string
+		got, want interface{ String() string }
+	}{
+		{"instance_type", got.InstanceType, plan.InstanceType},
+		{"instance_name", got.InstanceName, plan.InstanceName},
+		{"instance_state", got.InstanceState, plan.InstanceState},
+		{"password_version", got.PasswordVersion, plan.PasswordVersion},
+		{"block_device_mappings", got.BlockDeviceMappings, bdm},
+		{"disable_api_termination", got.DisableApiTermination, state.DisableApiTermination},
+		{"disable_api_stop", got.DisableApiStop, plan.DisableApiStop},
+		{"tags_all", got.TagsAll, state.TagsAll},
+		{"state", got.State, state.State},
+		{"private_ip", got.PrivateIP, state.PrivateIP},
+		{"public_ip", got.PublicIP, state.PublicIP},
+		{"availability_zone", got.AvailabilityZone, state.AvailabilityZone},
+	} {
+		if tt.got.String() != tt.want.String() {
+			t.Errorf("%s 应为 %s，得到 %s", tt.name, tt.want, tt.got)
+		}
+	}
+}
@@ -0,0 +1,379 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package ec2
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/mulei1288/terraform-provider-bingocloud/internal/conns"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/awserr"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/service/ec2"
+	"golang.org/x/crypto/ssh"
+)
+
+// 确保实现了必需的接口
+var _ resource.Resource = &KeyPairResource{}
+var _ resource.ResourceWithImportState = &KeyPairResource{}
+var _ resource.ResourceWithValidateConfig = &KeyPairResource{}
+
+// errCodeInvalidKeyPairNotFound 密钥对不存在的错误码
+const errCodeInvalidKeyPairNotFound = "InvalidKeyPair.NotFound"
+
+// 默认超时时间
+const (
+	keyPairCreateTimeout = 5 * time.Minute
+	keyPairDeleteTimeout = 5 * time.Minute
+)
+
+// KeyPairResource 定义密钥对资源实现
+type KeyPairResource struct {
+	client *conns.BingoCloudClient
+}
+
+// KeyPairResourceModel 描述密钥对资源数据模型
+type KeyPairResourceModel struct {
+	// 必需参数
+	KeyName types.String `tfsdk:"key_name"`
+
+	// 可选参数
+	PublicKey types.String `tfsdk:"public_key"`
+
+	// 计算属性
+	ID          types.String `tfsdk:"id"`
+	KeyPairID   types.String `tfsdk:"key_pair_id"`
+	Fingerprint types.String `tfsdk:"fingerprint"`
+	PrivateKey  types.String `tfsdk:"private_key"`
+
+	// 超时配置
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// NewKeyPairResource 创建新的密钥对资源
+func NewKeyPairResource() resource.Resource {
+	return &KeyPairResource{}
+}
+
+// Metadata 返回资源类型名称
+func (r *KeyPairResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_key_pair"
+}
+
+// Configure 配置资源，接收 Provider 传递的客户端
+func (r *KeyPairResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*conns.BingoCloudClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"意外的资源配置类型",
+			fmt.Sprintf("期望 *conns.BingoCloudClient，得到: %T。请向 provider 开发者报告此问题。", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+// Schema 定义资源的属性架构
+func (r *KeyPairResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "管理 BingoCloud SSH 密钥对。配置 `public_key` 时导入已有的公钥，" +
+			"否则由平台生成密钥对并通过 `private_key` 返回私钥。私钥会以明文保存在 Terraform 状态中，生产环境建议导入自己的公钥",
+
+		Attributes: map[string]schema.Attribute{
+			// 必需参数
+			"key_name": schema.StringAttribute{
+				MarkdownDescription: "密钥对名称，实例的 `key_name` 引用该名称，修改时重建密钥对",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			// 可选参数
+			"public_key": schema.StringAttribute{
+				MarkdownDescription: "OpenSSH 格式的公钥（如 `ssh-rsa AAAA... user@host`），修改时重建密钥对。" +
+					"不配置时由平台生成密钥对，该属性为生成的公钥",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+
+			// 计算属性（只读）
+			"id": schema.StringAttribute{
+				MarkdownDescription: "密钥对名称",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"key_pair_id": schema.StringAttribute{
+				MarkdownDescription: "密钥对 ID",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"fingerprint": schema.StringAttribute{
+				MarkdownDescription: "密钥对指纹",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"private_key": schema.StringAttribute{
+				MarkdownDescription: "平台生成的 PEM 格式私钥，仅在未配置 `public_key` 时创建返回，导入的资源为空",
+				Computed:            true,
+				Sensitive:           true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// Create 导入公钥或生成密钥对
+func (r *KeyPairResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan KeyPairResourceModel
+
+	// 读取 Terraform 计划数据
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, keyPairCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	conn := r.client.EC2Client()
+	keyName := plan.KeyName.ValueString()
+
+	if !plan.PublicKey.IsNull() && !plan.PublicKey.IsUnknown() {
+		tflog.Debug(ctx, "导入 BingoCloud 密钥对公钥", map[string]interface{}{
+			"key_name": keyName,
+		})
+
+		result, err := conn.ImportKeyPairWithContext(ctx, &ec2.ImportKeyPairInput{
+			KeyName:           aws.String(keyName),
+			PublicKeyMaterial: []byte(plan.PublicKey.ValueString()),
+		})
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"导入密钥对失败",
+				"无法导入密钥对 "+keyName+": "+err.Error(),
+			)
+			return
+		}
+
+		plan.Fingerprint = types.StringValue(aws.StringValue(result.KeyFingerprint))
+		plan.KeyPairID = stringValueOrNull(result.KeyPairId)
+		plan.PrivateKey = types.StringNull()
+	} else {
+		tflog.Debug(ctx, "生成 BingoCloud 密钥对", map[string]interface{}{
+			"key_name": keyName,
+		})
+
+		result, err := conn.CreateKeyPairWithContext(ctx, &ec2.CreateKeyPairInput{
+			KeyName: aws.String(keyName),
+		})
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"创建密钥对失败",
+				"无法创建密钥对 "+keyName+": "+err.Error(),
+			)
+			return
+		}
+
+		privateKey := aws.StringValue(result.KeyMaterial)
+		plan.Fingerprint = types.StringValue(aws.StringValue(result.KeyFingerprint))
+		plan.KeyPairID = stringValueOrNull(result.KeyPairId)
+		plan.PrivateKey = types.StringValue(privateKey)
+		plan.PublicKey = types.StringNull()
+
+		// 从私钥推导公钥，解析失败时保持为空，读取时再从 API 获取
+		if signer, err := ssh.ParsePrivateKey([]byte(privateKey)); err == nil {
+			plan.PublicKey = types.StringValue(strings.TrimSpace(string(ssh.MarshalAuthorizedKey(signer.PublicKey()))))
+		} else {
+			tflog.Debug(ctx, "无法从生成的私钥推导公钥", map[string]interface{}{
+				"key_name": keyName,
+				"error":    err.Error(),
+			})
+		}
+	}
+
+	plan.ID = types.StringValue(keyName)
+
+	tflog.Trace(ctx, "创建密钥对成功")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read 读取密钥对状态
+func (r *KeyPairResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state KeyPairResourceModel
+
+	// 读取 Terraform 状态数据
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	keyPair, err := findKeyPairByName(ctx, r.client.EC2Client(), state.ID.ValueString())
+	if err != nil {
+		if isErrorCode(err, errCodeInvalidKeyPairNotFound) {
+			// 密钥对不存在，从状态中移除
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"读取密钥对失败",
+			"无法读取密钥对 "+state.ID.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	state.KeyName = types.StringValue(aws.StringValue(keyPair.KeyName))
+	state.KeyPairID = stringValueOrNull(keyPair.KeyPairId)
+	state.Fingerprint = types.StringValue(aws.StringValue(keyPair.KeyFingerprint))
+
+	// 公钥保持配置的写法（API 返回的公钥可能不含注释），只在状态中没有公钥时（如导入）从 API 获取
+	if state.PublicKey.IsNull() {
+		state.PublicKey = stringValueOrNull(keyPair.PublicKey)
+	}
+	if state.PrivateKey.IsUnknown() {
+		state.PrivateKey = types.StringNull()
+	}
+
+	// 保存更新后的状态
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update 除 timeouts 外所有参数修改时都会重建密钥对，无需调用 API
+func (r *KeyPairResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan KeyPairResourceModel
+
+	// 读取 Terraform 计划数据
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete 删除密钥对
+func (r *KeyPairResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state KeyPairResourceModel
+
+	// 读取 Terraform 状态数据
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, keyPairDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	keyName := state.ID.ValueString()
+
+	tflog.Debug(ctx, "删除 BingoCloud 密钥对", map[string]interface{}{
+		"key_name": keyName,
+	})
+
+	_, err := r.client.EC2Client().DeleteKeyPairWithContext(ctx, &ec2.DeleteKeyPairInput{
+		KeyName: aws.String(keyName),
+	})
+	if err != nil && !isErrorCode(err, errCodeInvalidKeyPairNotFound) {
+		resp.Diagnostics.AddError(
+			"删除密钥对失败",
+			"无法删除密钥对 "+keyName+": "+err.Error(),
+		)
+		return
+	}
+
+	tflog.Trace(ctx, "删除密钥对成功")
+}
+
+// ValidateConfig 校验公钥为 OpenSSH 格式
+func (r *KeyPairResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var publicKey types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("public_key"), &publicKey)...)
+	if resp.Diagnostics.HasError() || publicKey.IsNull() || publicKey.IsUnknown() {
+		return
+	}
+
+	if _, _, _, _, err := ssh.ParseAuthorizedKey([]byte(publicKey.ValueString())); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("public_key"),
+			"无效的公钥",
+			"public_key 必须是 OpenSSH 格式的公钥（如 ssh-rsa AAAA... user@host）: "+err.Error(),
+		)
+	}
+}
+
+// ImportState 支持通过密钥对名称导入资源
+func (r *KeyPairResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// findKeyPairByName 根据名称查询密钥对，密钥对不存在时返回 InvalidKeyPair.NotFound 错误
+func findKeyPairByName(ctx context.Context, conn *ec2.EC2, name string) (*ec2.KeyPairInfo, error) {
+	result, err := conn.DescribeKeyPairsWithContext(ctx, &ec2.DescribeKeyPairsInput{
+		KeyNames:         []*string{aws.String(name)},
+		IncludePublicKey: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.KeyPairs) == 0 || result.KeyPairs[0] == nil {
+		return nil, awserr.New(errCodeInvalidKeyPairNotFound, "密钥对 "+name+" 不存在", nil)
+	}
+
+	return result.KeyPairs[0], nil
+}
+
+// stringValueOrNull 将 API 返回的字符串转换为 types.String，空值转换为 null
+func stringValueOrNull(v *string) types.String {
+	if aws.StringValue(v) == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(aws.StringValue(v))
+}
@@ -0,0 +1,57 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package ec2_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/mulei1288/terraform-provider-bingocloud/internal/acctest"
+)
+
+// testAccKeyPairPublicKey 测试用的 OpenSSH 公钥
+const testAccKeyPairPublicKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIEVq1zWZSFCtkNHF3ztqBJHXmg5VAYc6p5BVr8xlqIBN terraform-test"
+
+// testAccKeyPairConfig 生成密钥对资源的测试配置，分别导入公钥和由平台生成密钥对
+func testAccKeyPairConfig(name string) string {
+	return acctest.ProviderConfig() + fmt.Sprintf(`
+resource "bingocloud_key_pair" "imported" {
+  key_name   = "%[1]s-imported"
+  public_key = %[2]q
+}
+
+resource "bingocloud_key_pair" "generated" {
+  key_name = "%[1]s-generated"
+}
+`, name, testAccKeyPairPublicKey)
+}
+
+// TestAccKeyPairResource_basic 测试导入公钥、生成密钥对和导入状态
+func TestAccKeyPairResource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// 创建和读取测试
+			{
+				Config: testAccKeyPairConfig("test-kp"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("bingocloud_key_pair.imported", "public_key", testAccKeyPairPublicKey),
+					resource.TestCheckResourceAttrSet("bingocloud_key_pair.imported", "fingerprint"),
+					resource.TestCheckNoResourceAttr("bingocloud_key_pair.imported", "private_key"),
+					resource.TestCheckResourceAttrSet("bingocloud_key_pair.generated", "fingerprint"),
+					resource.TestCheckResourceAttrSet("bingocloud_key_pair.generated", "private_key"),
+				),
+			},
+			// 导入状态测试，API 返回的公钥可能不含注释，私钥只在创建时返回
+			{
+				ResourceName:            "bingocloud_key_pair.imported",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"public_key"},
+			},
+		},
+	})
+}
@@ -0,0 +1,704 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package ec2
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/mulei1288/terraform-provider-bingocloud/internal/conns"
+	tftags "github.com/mulei1288/terraform-provider-bingocloud/internal/tags"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/awserr"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/service/ec2"
+)
+
+// 确保实现了必需的接口
+var _ resource.Resource = &SecurityGroupResource{}
+var _ resource.ResourceWithImportState = &SecurityGroupResource{}
+var _ resource.ResourceWithModifyPlan = &SecurityGroupResource{}
+var _ resource.ResourceWithValidateConfig = &SecurityGroupResource{}
+
+// 默认超时时间
+const (
+	securityGroupCreateTimeout = 10 * time.Minute
+	securityGroupUpdateTimeout = 10 * time.Minute
+	securityGroupDeleteTimeout = 15 * time.Minute
+)
+
+// errCodeInvalidGroupNotFound 安全组不存在的错误码
+const errCodeInvalidGroupNotFound = "InvalidGroup.NotFound"
+
+// SecurityGroupResource 定义安全组资源实现
+type SecurityGroupResource struct {
+	client *conns.BingoCloudClient
+}
+
+// SecurityGroupResourceModel 描述安全组资源数据模型
+type SecurityGroupResourceModel struct {
+	// 必需参数
+	Name types.String `tfsdk:"name"`
+
+	// 可选参数
+	Description types.String `tfsdk:"description"`
+	VpcID       types.String `tfsdk:"vpc_id"`
+	Ingress     types.Set    `tfsdk:"ingress"`
+	Egress      types.Set    `tfsdk:"egress"`
+	Tags        types.Map    `tfsdk:"tags"`
+
+	// 计算属性
+	ID      types.String `tfsdk:"id"`
+	TagsAll types.Map    `tfsdk:"tags_all"`
+
+	// 超时配置
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// NewSecurityGroupResource 创建新的安全组资源
+func NewSecurityGroupResource() resource.Resource {
+	return &SecurityGroupResource{}
+}
+
+// Metadata 返回资源类型名称
+func (r *SecurityGroupResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_security_group"
+}
+
+// Configure 配置资源，接收 Provider 传递的客户端
+func (r *SecurityGroupResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*conns.BingoCloudClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"意外的资源配置类型",
+			fmt.Sprintf("期望 *conns.BingoCloudClient，得到: %T。请向 provider 开发者报告此问题。", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+// securityGroupRuleSetAttribute 返回内联规则集合的架构
+func securityGroupRuleSetAttribute(description string) schema.SetNestedAttribute {
+	return schema.SetNestedAttribute{
+		MarkdownDescription: description,
+		Optional:            true,
+		Computed:            true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"protocol": schema.StringAttribute{
+					MarkdownDescription: "协议：`tcp`、`udp`、`icmp`，`all` 或 `-1` 表示全部协议",
+					Required:            true,
+					Validators: []validator.String{
+						stringvalidator.OneOfCaseInsensitive(securityGroupProtocols...),
+					},
+				},
+				"from_port": schema.Int64Attribute{
+					MarkdownDescription: "起始端口，ICMP 协议为类型，全部协议时为 0",
+					Required:            true,
+				},
+				"to_port": schema.Int64Attribute{
+					MarkdownDescription: "结束端口，ICMP 协议为代码，全部协议时为 0",
+					Required:            true,
+				},
+				"cidr_blocks": schema.SetAttribute{
+					MarkdownDescription: "允许的 IPv4 网段",
+					ElementType:         types.StringType,
+					Optional:            true,
+				},
+				"security_groups": schema.SetAttribute{
+					MarkdownDescription: "允许的来源（入方向）或目标（出方向）安全组 ID",
+					ElementType:         types.StringType,
+					Optional:            true,
+				},
+			},
+		},
+		PlanModifiers: []planmodifier.Set{
+			setplanmodifier.UseStateForUnknown(),
+		},
+	}
+}
+
+// Schema 定义资源的属性架构
+func (r *SecurityGroupResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "管理 BingoCloud 安全组。规则可以通过内联的 `ingress`、`egress` 配置，" +
+			"也可以通过独立的 `bingocloud_security_group_rule` 资源配置，同一个安全组只能使用其中一种方式",
+
+		Attributes: map[string]schema.Attribute{
+			// 必需参数
+			"name": schema.StringAttribute{
+				MarkdownDescription: "安全组名称，修改时重建安全组",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			// 可选参数
+			"description": schema.StringAttribute{
+				MarkdownDescription: "安全组描述，修改时重建安全组，默认 `Managed by Terraform`",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("Managed by Terraform"),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"vpc_id": schema.StringAttribute{
+				MarkdownDescription: "安全组所属的 VPC ID，修改时重建安全组",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ingress": securityGroupRuleSetAttribute("入方向规则。配置后由 Terraform 管理全部入方向规则，规则的顺序不影响结果；" +
+				"设置为 `[]` 删除全部入方向规则，不配置时保持现有规则"),
+			"egress": securityGroupRuleSetAttribute("出方向规则。配置后由 Terraform 管理全部出方向规则，包括创建安全组时默认添加的允许全部出流量规则；" +
+				"设置为 `[]` 删除全部出方向规则，不配置时保持现有规则"),
+			"tags": tftags.TagsAttribute(),
+
+			// 计算属性（只读）
+			"id": schema.StringAttribute{
+				MarkdownDescription: "安全组 ID",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tags_all": tftags.TagsAllAttribute(),
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// Create 创建安全组并配置内联规则
+func (r *SecurityGroupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan SecurityGroupResourceModel
+
+	// 读取 Terraform 计划数据
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, securityGroupCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	conn := r.client.EC2Client()
+
+	input := &ec2.CreateSecurityGroupInput{
+		GroupName:   aws.String(plan.Name.ValueString()),
+		Description: aws.String(plan.Description.ValueString()),
+	}
+	if !plan.VpcID.IsNull() && !plan.VpcID.IsUnknown() {
+		input.VpcId = aws.String(plan.VpcID.ValueString())
+	}
+
+	tflog.Debug(ctx, "创建 BingoCloud 安全组", map[string]interface{}{
+		"name":   plan.Name.ValueString(),
+		"vpc_id": plan.VpcID.ValueString(),
+	})
+
+	result, err := conn.CreateSecurityGroupWithContext(ctx, input)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"创建安全组失败",
+			"无法创建安全组: "+err.Error(),
+		)
+		return
+	}
+
+	groupID := aws.StringValue(result.GroupId)
+	plan.ID = types.StringValue(groupID)
+
+	// 立即保存安全组 ID，后续步骤失败时资源会被标记为 tainted
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), plan.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("timeouts"), plan.Timeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// 标签：创建后通过 CreateTags 添加
+	tags, diags := tagsMapValue(ctx, plan.TagsAll)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if err := updateTags(ctx, conn, groupID, nil, tags); err != nil {
+		resp.Diagnostics.AddError(
+			"设置安全组标签失败",
+			"无法为安全组 "+groupID+" 设置标签: "+err.Error(),
+		)
+		return
+	}
+
+	group, err := findSecurityGroupByID(ctx, conn, groupID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"读取安全组详情失败",
+			"安全组创建成功但无法读取详细信息: "+err.Error(),
+		)
+		return
+	}
+
+	// 内联规则：以创建后的实际规则（如默认的出方向规则）为基准调整到配置的规则
+	for _, rules := range []struct {
+		ruleType string
+		set      types.Set
+	}{
+		{securityGroupRuleTypeIngress, plan.Ingress},
+		{securityGroupRuleTypeEgress, plan.Egress},
+	} {
+		if rules.set.IsUnknown() {
+			continue
+		}
+		newPerms, diags := expandSecurityGroupRules(ctx, rules.set)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(updateSecurityGroupPermissions(ctx, conn, groupID, rules.ruleType, securityGroupPermissionsByType(group, rules.ruleType), newPerms)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	group, err = findSecurityGroupByID(ctx, conn, groupID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"读取安全组详情失败",
+			"安全组创建成功但无法读取详细信息: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(flattenSecurityGroup(ctx, group, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "创建安全组成功")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read 读取安全组状态
+func (r *SecurityGroupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state SecurityGroupResourceModel
+
+	// 读取 Terraform 状态数据
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	group, err := findSecurityGroupByID(ctx, r.client.EC2Client(), state.ID.ValueString())
+	if err != nil {
+		if isErrorCode(err, errCodeInvalidGroupNotFound) {
+			// 安全组不存在，从状态中移除
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"读取安全组失败",
+			"无法读取安全组 "+state.ID.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	state.Name = types.StringValue(aws.StringValue(group.GroupName))
+	state.Description = types.StringValue(aws.StringValue(group.Description))
+
+	resp.Diagnostics.Append(flattenSecurityGroup(ctx, group, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// 标签：按 provider 的默认标签和忽略标签配置拆分到 tags 和 tags_all
+	tagsValue, tagsAllValue, diags := tftags.Flatten(ctx, tagsToMap(group.Tags), state.Tags, r.client.DefaultTagsConfig, r.client.IgnoreTagsConfig)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	state.Tags = tagsValue
+	state.TagsAll = tagsAllValue
+
+	// 保存更新后的状态
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update 更新安全组的内联规则和标签
+func (r *SecurityGroupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state SecurityGroupResourceModel
+
+	// 读取计划数据和当前状态
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, securityGroupUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	conn := r.client.EC2Client()
+	groupID := state.ID.ValueString()
+
+	// 内联规则：按单条授权比较，只撤销和添加发生变化的授权
+	for _, rules := range []struct {
+		ruleType    string
+		plan, state types.Set
+	}{
+		{securityGroupRuleTypeIngress, plan.Ingress, state.Ingress},
+		{securityGroupRuleTypeEgress, plan.Egress, state.Egress},
+	} {
+		if rules.plan.IsUnknown() || rules.plan.Equal(rules.state) {
+			continue
+		}
+		oldPerms, diags := expandSecurityGroupRules(ctx, rules.state)
+		resp.Diagnostics.Append(diags...)
+		newPerms, diags := expandSecurityGroupRules(ctx, rules.plan)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(updateSecurityGroupPermissions(ctx, conn, groupID, rules.ruleType, oldPerms, newPerms)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// 标签：按包含默认标签的 tags_all 比较
+	if !plan.TagsAll.Equal(state.TagsAll) {
+		oldTags, diags := tagsMapValue(ctx, state.TagsAll)
+		resp.Diagnostics.Append(diags...)
+		newTags, diags := tagsMapValue(ctx, plan.TagsAll)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if err := updateTags(ctx, conn, groupID, oldTags, newTags); err != nil {
+			resp.Diagnostics.AddError(
+				"更新安全组标签失败",
+				"无法更新安全组 "+groupID+" 的标签: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	// 未配置的内联规则保持状态中的值
+	if plan.Ingress.IsUnknown() {
+		plan.Ingress = state.Ingress
+	}
+	if plan.Egress.IsUnknown() {
+		plan.Egress = state.Egress
+	}
+
+	tflog.Trace(ctx, "更新安全组成功")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete 删除安全组
+// 先撤销其它安全组中引用该安全组的规则，避免互相引用的安全组无法删除；
+// 使用该安全组的实例正在终止时 API 返回 DependencyViolation，等待后重试
+func (r *SecurityGroupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state SecurityGroupResourceModel
+
+	// 读取 Terraform 状态数据
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, securityGroupDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	conn := r.client.EC2Client()
+	groupID := state.ID.ValueString()
+
+	if err := revokeSecurityGroupReferences(ctx, conn, groupID, state.VpcID.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"删除安全组失败",
+			"无法撤销其它安全组中引用安全组 "+groupID+" 的规则: "+err.Error(),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, "删除 BingoCloud 安全组", map[string]interface{}{
+		"group_id": groupID,
+	})
+
+	err := retryWhenErrorCode(ctx, errCodeDependencyViolation, func() error {
+		_, err := conn.DeleteSecurityGroupWithContext(ctx, &ec2.DeleteSecurityGroupInput{
+			GroupId: aws.String(groupID),
+		})
+		return err
+	})
+	if err != nil && !isErrorCode(err, errCodeInvalidGroupNotFound) {
+		resp.Diagnostics.AddError(
+			"删除安全组失败",
+			"无法删除安全组 "+groupID+": "+waitErrorDetail(ctx, "删除", deleteTimeout, err, securityGroupLastState(conn, groupID)),
+		)
+		return
+	}
+
+	tflog.Trace(ctx, "删除安全组成功")
+}
+
+// ValidateConfig 校验内联规则
+func (r *SecurityGroupResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config SecurityGroupResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, rules := range []struct {
+		name string
+		set  types.Set
+	}{
+		{"ingress", config.Ingress},
+		{"egress", config.Egress},
+	} {
+		if rules.set.IsNull() || rules.set.IsUnknown() {
+			continue
+		}
+
+		var models []SecurityGroupRuleModel
+		resp.Diagnostics.Append(rules.set.ElementsAs(ctx, &models, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		for _, rule := range models {
+			hasSource := (!rule.CidrBlocks.IsNull() && len(rule.CidrBlocks.Elements()) > 0) || rule.CidrBlocks.IsUnknown() ||
+				(!rule.SecurityGroups.IsNull() && len(rule.SecurityGroups.Elements()) > 0) || rule.SecurityGroups.IsUnknown()
+			resp.Diagnostics.Append(validateSecurityGroupRule(ctx, path.Root(rules.name), rule.Protocol, rule.FromPort, rule.ToPort, rule.CidrBlocks, hasSource)...)
+		}
+	}
+}
+
+// ModifyPlan 调整计划：计算 tags_all
+func (r *SecurityGroupResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// 销毁时无需调整
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	// 合并 provider 的默认标签
+	if r.client != nil {
+		resp.Diagnostics.Append(tftags.ModifyPlan(ctx, r.client.DefaultTagsConfig, r.client.IgnoreTagsConfig, &resp.Plan)...)
+	}
+}
+
+// ImportState 支持通过安全组 ID 导入资源
+func (r *SecurityGroupResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// validateSecurityGroupRule 校验单条规则：必须指定来源，网段有效，端口范围有效
+func validateSecurityGroupRule(ctx context.Context, p path.Path, protocol types.String, fromPort, toPort types.Int64, cidrBlocks types.Set, hasSource bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !hasSource {
+		diags.AddAttributeError(p, "缺少规则来源", "安全组规则必须至少配置一个网段或安全组")
+	}
+
+	if !cidrBlocks.IsNull() && !cidrBlocks.IsUnknown() {
+		var cidrs []types.String
+		diags.Append(cidrBlocks.ElementsAs(ctx, &cidrs, false)...)
+		for _, cidr := range cidrs {
+			diags.Append(validateCidrBlock(cidr, p)...)
+		}
+	}
+
+	if protocol.IsUnknown() || fromPort.IsUnknown() || toPort.IsUnknown() {
+		return diags
+	}
+	switch normalizeSecurityGroupProtocol(protocol.ValueString()) {
+	case "tcp", "udp":
+		if fromPort.ValueInt64() < 0 || toPort.ValueInt64() > 65535 || fromPort.ValueInt64() > toPort.ValueInt64() {
+			diags.AddAttributeError(p, "无效的端口范围", fmt.Sprintf("端口范围 %d-%d 无效，端口必须在 0-65535 之间且 from_port 不大于 to_port", fromPort.ValueInt64(), toPort.ValueInt64()))
+		}
+	case securityGroupProtocolAll:
+		if fromPort.ValueInt64() != 0 || toPort.ValueInt64() != 0 {
+			diags.AddAttributeError(p, "无效的端口范围", "协议为全部协议时 from_port 和 to_port 必须为 0")
+		}
+	}
+
+	return diags
+}
+
+// updateSecurityGroupPermissions 将安全组指定方向的授权从 oldPerms 调整为 newPerms，先撤销再添加
+func updateSecurityGroupPermissions(ctx context.Context, conn *ec2.EC2, groupID, ruleType string, oldPerms, newPerms []securityGroupPermission) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	revoke, authorize := diffSecurityGroupPermissions(oldPerms, newPerms)
+
+	if err := revokeSecurityGroupPermissions(ctx, conn, groupID, ruleType, revoke); err != nil {
+		diags.AddError(
+			"撤销安全组规则失败",
+			"无法撤销安全组 "+groupID+" 的 "+ruleType+" 规则: "+err.Error(),
+		)
+		return diags
+	}
+	if err := authorizeSecurityGroupPermissions(ctx, conn, groupID, ruleType, authorize); err != nil {
+		diags.AddError(
+			"添加安全组规则失败",
+			"无法为安全组 "+groupID+" 添加 "+ruleType+" 规则: "+err.Error(),
+		)
+		return diags
+	}
+
+	return diags
+}
+
+// revokeSecurityGroupReferences 撤销其它安全组中以 groupID 为来源或目标的规则
+// vpcID 不为空时只检查同一 VPC 中的安全组
+func revokeSecurityGroupReferences(ctx context.Context, conn *ec2.EC2, groupID, vpcID string) error {
+	input := &ec2.DescribeSecurityGroupsInput{}
+	if vpcID != "" {
+		input.Filters = []*ec2.Filter{
+			{Name: aws.String("vpc-id"), Values: []*string{aws.String(vpcID)}},
+		}
+	}
+
+	var groups []*ec2.SecurityGroup
+	err := conn.DescribeSecurityGroupsPagesWithContext(ctx, input, func(page *ec2.DescribeSecurityGroupsOutput, lastPage bool) bool {
+		groups = append(groups, page.SecurityGroups...)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, group := range groups {
+		otherID := aws.StringValue(group.GroupId)
+		if otherID == groupID {
+			continue
+		}
+
+		for _, ruleType := range []string{securityGroupRuleTypeIngress, securityGroupRuleTypeEgress} {
+			var referencing []securityGroupPermission
+			for _, p := range securityGroupPermissionsByType(group, ruleType) {
+				if p.GroupID == groupID {
+					referencing = append(referencing, p)
+				}
+			}
+			if len(referencing) == 0 {
+				continue
+			}
+
+			tflog.Debug(ctx, "撤销引用待删除安全组的规则", map[string]interface{}{
+				"group_id":            otherID,
+				"referenced_group_id": groupID,
+				"type":                ruleType,
+			})
+
+			err := revokeSecurityGroupPermissions(ctx, conn, otherID, ruleType, referencing)
+			if err != nil && !isErrorCode(err, errCodeInvalidGroupNotFound) {
+				return fmt.Errorf("撤销安全组 %s 的规则失败: %w", otherID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// findSecurityGroupByID 根据安全组 ID 查询安全组，安全组不存在时返回 InvalidGroup.NotFound 错误
+func findSecurityGroupByID(ctx context.Context, conn *ec2.EC2, id string) (*ec2.SecurityGroup, error) {
+	result, err := conn.DescribeSecurityGroupsWithContext(ctx, &ec2.DescribeSecurityGroupsInput{
+		GroupIds: []*string{aws.String(id)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.SecurityGroups) == 0 || result.SecurityGroups[0] == nil {
+		return nil, awserr.New(errCodeInvalidGroupNotFound, "安全组 "+id+" 不存在", nil)
+	}
+
+	return result.SecurityGroups[0], nil
+}
+
+// securityGroupLastState 返回查询安全组最后状态的函数，安全组没有状态字段，返回是否仍然存在
+func securityGroupLastState(conn *ec2.EC2, id string) func(context.Context) string {
+	return func(ctx context.Context) string {
+		if _, err := findSecurityGroupByID(ctx, conn, id); err != nil {
+			if isErrorCode(err, errCodeInvalidGroupNotFound) {
+				return "deleted"
+			}
+			return "unknown"
+		}
+		return "exists"
+	}
+}
+
+// flattenSecurityGroup 将安全组的 VPC 和规则写入模型，规则以模型中原有的值为基准
+func flattenSecurityGroup(ctx context.Context, group *ec2.SecurityGroup, model *SecurityGroupResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	model.VpcID = types.StringNull()
+	if v := aws.StringValue(group.VpcId); v != "" {
+		model.VpcID = types.StringValue(v)
+	}
+
+	ingress, d := flattenSecurityGroupRules(ctx, flattenIpPermissions(group.IpPermissions), model.Ingress)
+	diags.Append(d...)
+	egress, d := flattenSecurityGroupRules(ctx, flattenIpPermissions(group.IpPermissionsEgress), model.Egress)
+	diags.Append(d...)
+	if diags.HasError() {
+		return diags
+	}
+
+	model.Ingress = ingress
+	model.Egress = egress
+	return diags
+}
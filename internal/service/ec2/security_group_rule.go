@@ -0,0 +1,463 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package ec2
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/mulei1288/terraform-provider-bingocloud/internal/conns"
+)
+
+// 确保实现了必需的接口
+var _ resource.Resource = &SecurityGroupRuleResource{}
+var _ resource.ResourceWithImportState = &SecurityGroupRuleResource{}
+var _ resource.ResourceWithValidateConfig = &SecurityGroupRuleResource{}
+
+// 安全组规则相关的错误码
+const (
+	errCodeInvalidPermissionDuplicate = "InvalidPermission.Duplicate"
+	errCodeInvalidPermissionNotFound  = "InvalidPermission.NotFound"
+)
+
+// 默认超时时间
+const (
+	securityGroupRuleCreateTimeout = 5 * time.Minute
+	securityGroupRuleDeleteTimeout = 5 * time.Minute
+)
+
+// SecurityGroupRuleResource 定义安全组规则资源实现
+type SecurityGroupRuleResource struct {
+	client *conns.BingoCloudClient
+}
+
+// SecurityGroupRuleResourceModel 描述安全组规则资源数据模型
+type SecurityGroupRuleResourceModel struct {
+	// 必需参数
+	Type            types.String `tfsdk:"type"`
+	SecurityGroupID types.String `tfsdk:"security_group_id"`
+	Protocol        types.String `tfsdk:"protocol"`
+	FromPort        types.Int64  `tfsdk:"from_port"`
+	ToPort          types.Int64  `tfsdk:"to_port"`
+
+	// 可选参数
+	CidrBlocks            types.Set    `tfsdk:"cidr_blocks"`
+	SourceSecurityGroupID types.String `tfsdk:"source_security_group_id"`
+
+	// 计算属性
+	ID types.String `tfsdk:"id"`
+
+	// 超时配置
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// NewSecurityGroupRuleResource 创建新的安全组规则资源
+func NewSecurityGroupRuleResource() resource.Resource {
+	return &SecurityGroupRuleResource{}
+}
+
+// Metadata 返回资源类型名称
+func (r *SecurityGroupRuleResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_security_group_rule"
+}
+
+// Configure 配置资源，接收 Provider 传递的客户端
+func (r *SecurityGroupRuleResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*conns.BingoCloudClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"意外的资源配置类型",
+			fmt.Sprintf("期望 *conns.BingoCloudClient，得到: %T。请向 provider 开发者报告此问题。", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+// Schema 定义资源的属性架构
+func (r *SecurityGroupRuleResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "管理 BingoCloud 安全组的单条入方向或出方向规则。" +
+			"不要对同一个安全组同时使用本资源和 `bingocloud_security_group` 的内联 `ingress`、`egress` 规则，否则两者会互相覆盖",
+
+		Attributes: map[string]schema.Attribute{
+			// 必需参数
+			"type": schema.StringAttribute{
+				MarkdownDescription: "规则方向：`ingress` 或 `egress`，修改时重建规则",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(securityGroupRuleTypeIngress, securityGroupRuleTypeEgress),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"security_group_id": schema.StringAttribute{
+				MarkdownDescription: "规则所属的安全组 ID，修改时重建规则",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"protocol": schema.StringAttribute{
+				MarkdownDescription: "协议：`tcp`、`udp`、`icmp`，`all` 或 `-1` 表示全部协议，修改时重建规则",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOfCaseInsensitive(securityGroupProtocols...),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"from_port": schema.Int64Attribute{
+				MarkdownDescription: "起始端口，ICMP 协议为类型，全部协议时为 0，修改时重建规则",
+				Required:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"to_port": schema.Int64Attribute{
+				MarkdownDescription: "结束端口，ICMP 协议为代码，全部协议时为 0，修改时重建规则",
+				Required:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+
+			// 可选参数
+			"cidr_blocks": schema.SetAttribute{
+				MarkdownDescription: "允许的 IPv4 网段，与 `source_security_group_id` 二选一，修改时重建规则",
+				ElementType:         types.StringType,
+				Optional:            true,
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.RequiresReplace(),
+				},
+			},
+			"source_security_group_id": schema.StringAttribute{
+				MarkdownDescription: "允许的来源（入方向）或目标（出方向）安全组 ID，与 `cidr_blocks` 二选一，修改时重建规则",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			// 计算属性（只读）
+			"id": schema.StringAttribute{
+				MarkdownDescription: "规则 ID，由安全组、方向、协议、端口范围和来源计算得到",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// Create 添加安全组规则
+func (r *SecurityGroupRuleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan SecurityGroupRuleResourceModel
+
+	// 读取 Terraform 计划数据
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, securityGroupRuleCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	perms, diags := plan.permissions(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn := r.client.EC2Client()
+	groupID := plan.SecurityGroupID.ValueString()
+	ruleType := plan.Type.ValueString()
+
+	tflog.Debug(ctx, "添加 BingoCloud 安全组规则", map[string]interface{}{
+		"group_id": groupID,
+		"type":     ruleType,
+		"protocol": plan.Protocol.ValueString(),
+	})
+
+	if err := authorizeSecurityGroupPermissions(ctx, conn, groupID, ruleType, perms); err != nil {
+		detail := "无法为安全组 " + groupID + " 添加规则: " + err.Error()
+		if isErrorCode(err, errCodeInvalidPermissionDuplicate) {
+			detail += "\n\n规则已存在，可能由其它 bingocloud_security_group_rule 资源或安全组的内联规则管理，可以通过 terraform import 导入"
+		}
+		resp.Diagnostics.AddError("添加安全组规则失败", detail)
+		return
+	}
+
+	plan.ID = types.StringValue(securityGroupRuleHash(ruleType, groupID, perms))
+
+	tflog.Trace(ctx, "添加安全组规则成功")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read 读取安全组规则状态，规则的任意一条授权不存在时从状态中移除
+func (r *SecurityGroupRuleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state SecurityGroupRuleResourceModel
+
+	// 读取 Terraform 状态数据
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	groupID := state.SecurityGroupID.ValueString()
+	group, err := findSecurityGroupByID(ctx, r.client.EC2Client(), groupID)
+	if err != nil {
+		if isErrorCode(err, errCodeInvalidGroupNotFound) {
+			// 安全组不存在，规则随之删除
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"读取安全组规则失败",
+			"无法读取安全组 "+groupID+": "+err.Error(),
+		)
+		return
+	}
+
+	perms, diags := state.permissions(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	existing := make(map[string]struct{})
+	for _, p := range securityGroupPermissionsByType(group, state.Type.ValueString()) {
+		existing[p.key()] = struct{}{}
+	}
+	for _, p := range perms {
+		if _, ok := existing[p.key()]; !ok {
+			tflog.Debug(ctx, "安全组规则不存在，从状态中移除", map[string]interface{}{
+				"group_id":   groupID,
+				"permission": p.key(),
+			})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+	}
+
+	state.ID = types.StringValue(securityGroupRuleHash(state.Type.ValueString(), groupID, perms))
+
+	// 保存更新后的状态
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update 除 timeouts 外所有参数修改时都会重建规则，无需调用 API
+func (r *SecurityGroupRuleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan SecurityGroupRuleResourceModel
+
+	// 读取 Terraform 计划数据
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete 撤销安全组规则
+func (r *SecurityGroupRuleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state SecurityGroupRuleResourceModel
+
+	// 读取 Terraform 状态数据
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, securityGroupRuleDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	perms, diags := state.permissions(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	groupID := state.SecurityGroupID.ValueString()
+
+	tflog.Debug(ctx, "撤销 BingoCloud 安全组规则", map[string]interface{}{
+		"group_id": groupID,
+		"type":     state.Type.ValueString(),
+	})
+
+	err := revokeSecurityGroupPermissions(ctx, r.client.EC2Client(), groupID, state.Type.ValueString(), perms)
+	if err != nil && !isErrorCode(err, errCodeInvalidPermissionNotFound) && !isErrorCode(err, errCodeInvalidGroupNotFound) {
+		resp.Diagnostics.AddError(
+			"撤销安全组规则失败",
+			"无法撤销安全组 "+groupID+" 的规则: "+err.Error(),
+		)
+		return
+	}
+
+	tflog.Trace(ctx, "撤销安全组规则成功")
+}
+
+// ValidateConfig 校验规则来源和端口范围
+func (r *SecurityGroupRuleResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config SecurityGroupRuleResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	hasCidrBlocks := config.CidrBlocks.IsUnknown() || (!config.CidrBlocks.IsNull() && len(config.CidrBlocks.Elements()) > 0)
+	hasSourceGroup := !config.SourceSecurityGroupID.IsNull()
+
+	if hasCidrBlocks && hasSourceGroup {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("source_security_group_id"),
+			"规则来源冲突",
+			"cidr_blocks 和 source_security_group_id 只能配置其中一个",
+		)
+	}
+
+	resp.Diagnostics.Append(validateSecurityGroupRule(ctx, path.Root("cidr_blocks"), config.Protocol, config.FromPort, config.ToPort, config.CidrBlocks, hasCidrBlocks || hasSourceGroup)...)
+}
+
+// ImportState 支持导入安全组规则
+// 导入 ID 格式为 <安全组 ID>_<方向>_<协议>_<起始端口>_<结束端口>_<来源>[_<来源>...]，
+// 来源为网段或一个安全组 ID，例如 sg-12345678_ingress_tcp_80_80_10.0.0.0/16_10.1.0.0/16
+func (r *SecurityGroupRuleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	model, err := parseSecurityGroupRuleImportID(ctx, req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"无效的导入 ID",
+			"无法解析安全组规则导入 ID "+req.ID+": "+err.Error()+
+				"\n\n导入 ID 格式为 <安全组 ID>_<方向>_<协议>_<起始端口>_<结束端口>_<来源>[_<来源>...]",
+		)
+		return
+	}
+
+	// 导入时没有超时配置，保持为空
+	resp.Diagnostics.Append(resp.State.GetAttribute(ctx, path.Root("timeouts"), &model.Timeouts)...)
+
+	perms, diags := model.permissions(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	model.ID = types.StringValue(securityGroupRuleHash(model.Type.ValueString(), model.SecurityGroupID.ValueString(), perms))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+// permissions 将规则拆分为单条授权
+func (m SecurityGroupRuleResourceModel) permissions(ctx context.Context) ([]securityGroupPermission, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	var cidrBlocks, groupIDs []string
+	if !m.CidrBlocks.IsNull() && !m.CidrBlocks.IsUnknown() {
+		diags.Append(m.CidrBlocks.ElementsAs(ctx, &cidrBlocks, false)...)
+	}
+	if !m.SourceSecurityGroupID.IsNull() && !m.SourceSecurityGroupID.IsUnknown() {
+		groupIDs = append(groupIDs, m.SourceSecurityGroupID.ValueString())
+	}
+
+	return newSecurityGroupPermissions(m.Protocol.ValueString(), m.FromPort.ValueInt64(), m.ToPort.ValueInt64(), cidrBlocks, groupIDs), diags
+}
+
+// parseSecurityGroupRuleImportID 解析安全组规则的导入 ID
+func parseSecurityGroupRuleImportID(ctx context.Context, id string) (SecurityGroupRuleResourceModel, error) {
+	model := SecurityGroupRuleResourceModel{
+		CidrBlocks:            types.SetNull(types.StringType),
+		SourceSecurityGroupID: types.StringNull(),
+	}
+
+	parts := strings.Split(id, "_")
+	if len(parts) < 6 {
+		return model, fmt.Errorf("至少需要 6 个以 _ 分隔的部分，得到 %d 个", len(parts))
+	}
+
+	ruleType := parts[1]
+	if ruleType != securityGroupRuleTypeIngress && ruleType != securityGroupRuleTypeEgress {
+		return model, fmt.Errorf("方向必须为 %s 或 %s，得到 %q", securityGroupRuleTypeIngress, securityGroupRuleTypeEgress, ruleType)
+	}
+
+	fromPort, err := strconv.ParseInt(parts[3], 10, 64)
+	if err != nil {
+		return model, fmt.Errorf("无效的起始端口 %q", parts[3])
+	}
+	toPort, err := strconv.ParseInt(parts[4], 10, 64)
+	if err != nil {
+		return model, fmt.Errorf("无效的结束端口 %q", parts[4])
+	}
+
+	var cidrBlocks []string
+	for _, source := range parts[5:] {
+		if strings.HasPrefix(source, "sg-") {
+			if !model.SourceSecurityGroupID.IsNull() {
+				return model, fmt.Errorf("只能包含一个来源安全组")
+			}
+			model.SourceSecurityGroupID = types.StringValue(source)
+			continue
+		}
+		cidrBlocks = append(cidrBlocks, source)
+	}
+	if len(cidrBlocks) > 0 {
+		if !model.SourceSecurityGroupID.IsNull() {
+			return model, fmt.Errorf("网段和来源安全组只能包含其中一种")
+		}
+		set, diags := types.SetValueFrom(ctx, types.StringType, cidrBlocks)
+		if diags.HasError() {
+			return model, fmt.Errorf("无效的网段 %v", cidrBlocks)
+		}
+		model.CidrBlocks = set
+	}
+
+	model.SecurityGroupID = types.StringValue(parts[0])
+	model.Type = types.StringValue(ruleType)
+	model.Protocol = types.StringValue(parts[2])
+	model.FromPort = types.Int64Value(fromPort)
+	model.ToPort = types.Int64Value(toPort)
+	return model, nil
+}
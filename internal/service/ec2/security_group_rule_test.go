@@ -0,0 +1,109 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package ec2_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/mulei1288/terraform-provider-bingocloud/internal/acctest"
+)
+
+// testAccSecurityGroupRuleConfig 生成安全组规则资源的测试配置，两个安全组通过规则互相引用
+func testAccSecurityGroupRuleConfig(name string) string {
+	return acctest.ProviderConfig() + fmt.Sprintf(`
+resource "bingocloud_vpc" "test" {
+  cidr_block = "10.40.0.0/16"
+
+  tags = {
+    Name = %[1]q
+  }
+}
+
+resource "bingocloud_security_group" "web" {
+  name   = "%[1]s-web"
+  vpc_id = bingocloud_vpc.test.id
+}
+
+resource "bingocloud_security_group" "db" {
+  name   = "%[1]s-db"
+  vpc_id = bingocloud_vpc.test.id
+}
+
+resource "bingocloud_security_group_rule" "http" {
+  type              = "ingress"
+  security_group_id = bingocloud_security_group.web.id
+  protocol          = "tcp"
+  from_port         = 80
+  to_port           = 80
+  cidr_blocks       = ["0.0.0.0/0"]
+}
+
+resource "bingocloud_security_group_rule" "web_from_db" {
+  type                     = "ingress"
+  security_group_id        = bingocloud_security_group.web.id
+  protocol                 = "tcp"
+  from_port                = 8080
+  to_port                  = 8080
+  source_security_group_id = bingocloud_security_group.db.id
+}
+
+resource "bingocloud_security_group_rule" "db_from_web" {
+  type                     = "ingress"
+  security_group_id        = bingocloud_security_group.db.id
+  protocol                 = "tcp"
+  from_port                = 3306
+  to_port                  = 3306
+  source_security_group_id = bingocloud_security_group.web.id
+}
+`, name)
+}
+
+// testAccSecurityGroupRuleImportStateIdFunc 生成安全组规则的导入 ID
+func testAccSecurityGroupRuleImportStateIdFunc(resourceName string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return "", fmt.Errorf("资源不存在: %s", resourceName)
+		}
+
+		return fmt.Sprintf("%s_%s_%s_%s_%s_%s",
+			rs.Primary.Attributes["security_group_id"],
+			rs.Primary.Attributes["type"],
+			rs.Primary.Attributes["protocol"],
+			rs.Primary.Attributes["from_port"],
+			rs.Primary.Attributes["to_port"],
+			rs.Primary.Attributes["cidr_blocks.0"],
+		), nil
+	}
+}
+
+// TestAccSecurityGroupRuleResource_basic 测试安全组规则资源的创建、互相引用的安全组删除和导入
+func TestAccSecurityGroupRuleResource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// 创建和读取测试
+			{
+				Config: testAccSecurityGroupRuleConfig("test-sgrule"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("bingocloud_security_group_rule.http", "cidr_blocks.#", "1"),
+					resource.TestCheckResourceAttrPair("bingocloud_security_group_rule.web_from_db", "source_security_group_id", "bingocloud_security_group.db", "id"),
+					resource.TestCheckResourceAttrPair("bingocloud_security_group_rule.db_from_web", "source_security_group_id", "bingocloud_security_group.web", "id"),
+					resource.TestCheckResourceAttrSet("bingocloud_security_group_rule.http", "id"),
+				),
+			},
+			// 导入状态测试
+			{
+				ResourceName:      "bingocloud_security_group_rule.http",
+				ImportState:       true,
+				ImportStateIdFunc: testAccSecurityGroupRuleImportStateIdFunc("bingocloud_security_group_rule.http"),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
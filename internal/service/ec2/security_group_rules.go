@@ -0,0 +1,382 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package ec2
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/service/ec2"
+)
+
+// 安全组规则方向
+const (
+	securityGroupRuleTypeIngress = "ingress"
+	securityGroupRuleTypeEgress  = "egress"
+)
+
+// securityGroupProtocolAll 表示全部协议
+const securityGroupProtocolAll = "-1"
+
+// securityGroupProtocols 可配置的协议，all 与 -1 等价
+var securityGroupProtocols = []string{"tcp", "udp", "icmp", "all", securityGroupProtocolAll}
+
+// SecurityGroupRuleModel 描述安全组内联规则
+type SecurityGroupRuleModel struct {
+	Protocol       types.String `tfsdk:"protocol"`
+	FromPort       types.Int64  `tfsdk:"from_port"`
+	ToPort         types.Int64  `tfsdk:"to_port"`
+	CidrBlocks     types.Set    `tfsdk:"cidr_blocks"`
+	SecurityGroups types.Set    `tfsdk:"security_groups"`
+}
+
+// securityGroupRuleAttrTypes 安全组内联规则嵌套对象的属性类型
+var securityGroupRuleAttrTypes = map[string]attr.Type{
+	"protocol":        types.StringType,
+	"from_port":       types.Int64Type,
+	"to_port":         types.Int64Type,
+	"cidr_blocks":     types.SetType{ElemType: types.StringType},
+	"security_groups": types.SetType{ElemType: types.StringType},
+}
+
+// securityGroupPermission 单条授权：一个协议和端口范围加一个来源（网段或安全组）
+// API 的 IpPermission 可以包含多个来源，拆分后按来源逐条比较，规则的书写方式和顺序不影响比较结果
+type securityGroupPermission struct {
+	Protocol string
+	FromPort int64
+	ToPort   int64
+	CidrIP   string
+	GroupID  string
+}
+
+// key 返回授权的唯一标识
+func (p securityGroupPermission) key() string {
+	return fmt.Sprintf("%s_%d_%d_%s_%s", p.Protocol, p.FromPort, p.ToPort, p.CidrIP, p.GroupID)
+}
+
+// normalizeSecurityGroupProtocol 将协议统一为 API 返回的形式：小写名称，all 和空值为 -1，常用协议号转换为名称
+func normalizeSecurityGroupProtocol(protocol string) string {
+	switch p := strings.ToLower(protocol); p {
+	case "", "all":
+		return securityGroupProtocolAll
+	case "6":
+		return "tcp"
+	case "17":
+		return "udp"
+	case "1":
+		return "icmp"
+	default:
+		return p
+	}
+}
+
+// newSecurityGroupPermissions 按来源拆分规则，全部协议的端口范围统一为 0
+func newSecurityGroupPermissions(protocol string, fromPort, toPort int64, cidrBlocks, groupIDs []string) []securityGroupPermission {
+	protocol = normalizeSecurityGroupProtocol(protocol)
+	if protocol == securityGroupProtocolAll {
+		fromPort, toPort = 0, 0
+	}
+
+	perms := make([]securityGroupPermission, 0, len(cidrBlocks)+len(groupIDs))
+	for _, cidr := range cidrBlocks {
+		perms = append(perms, securityGroupPermission{Protocol: protocol, FromPort: fromPort, ToPort: toPort, CidrIP: cidr})
+	}
+	for _, groupID := range groupIDs {
+		perms = append(perms, securityGroupPermission{Protocol: protocol, FromPort: fromPort, ToPort: toPort, GroupID: groupID})
+	}
+	return perms
+}
+
+// securityGroupRuleHash 计算规则的哈希，来源按字典序排序，规则的书写顺序不影响结果
+func securityGroupRuleHash(ruleType, groupID string, perms []securityGroupPermission) string {
+	keys := make([]string, 0, len(perms))
+	for _, p := range perms {
+		keys = append(keys, p.key())
+	}
+	sort.Strings(keys)
+
+	return fmt.Sprintf("sgrule-%d", crc32.ChecksumIEEE([]byte(groupID+"_"+ruleType+"_"+strings.Join(keys, ","))))
+}
+
+// expandSecurityGroupRule 将内联规则拆分为单条授权
+func expandSecurityGroupRule(ctx context.Context, rule SecurityGroupRuleModel) ([]securityGroupPermission, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	var cidrBlocks, groupIDs []string
+	if !rule.CidrBlocks.IsNull() && !rule.CidrBlocks.IsUnknown() {
+		diags.Append(rule.CidrBlocks.ElementsAs(ctx, &cidrBlocks, false)...)
+	}
+	if !rule.SecurityGroups.IsNull() && !rule.SecurityGroups.IsUnknown() {
+		diags.Append(rule.SecurityGroups.ElementsAs(ctx, &groupIDs, false)...)
+	}
+
+	return newSecurityGroupPermissions(rule.Protocol.ValueString(), rule.FromPort.ValueInt64(), rule.ToPort.ValueInt64(), cidrBlocks, groupIDs), diags
+}
+
+// expandSecurityGroupRules 将内联规则集合拆分为单条授权，重复的授权只保留一条
+func expandSecurityGroupRules(ctx context.Context, set types.Set) ([]securityGroupPermission, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if set.IsNull() || set.IsUnknown() {
+		return nil, diags
+	}
+
+	var rules []SecurityGroupRuleModel
+	diags.Append(set.ElementsAs(ctx, &rules, false)...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	seen := make(map[string]struct{})
+	var perms []securityGroupPermission
+	for _, rule := range rules {
+		rulePerms, d := expandSecurityGroupRule(ctx, rule)
+		diags.Append(d...)
+		for _, p := range rulePerms {
+			if _, ok := seen[p.key()]; ok {
+				continue
+			}
+			seen[p.key()] = struct{}{}
+			perms = append(perms, p)
+		}
+	}
+
+	return perms, diags
+}
+
+// flattenIpPermissions 将 API 返回的授权拆分为单条授权
+func flattenIpPermissions(ipPermissions []*ec2.IpPermission) []securityGroupPermission {
+	var perms []securityGroupPermission
+	for _, ipPermission := range ipPermissions {
+		var cidrBlocks, groupIDs []string
+		for _, r := range ipPermission.IpRanges {
+			cidrBlocks = append(cidrBlocks, aws.StringValue(r.CidrIp))
+		}
+		for _, pair := range ipPermission.UserIdGroupPairs {
+			groupIDs = append(groupIDs, aws.StringValue(pair.GroupId))
+		}
+		perms = append(perms, newSecurityGroupPermissions(
+			aws.StringValue(ipPermission.IpProtocol),
+			aws.Int64Value(ipPermission.FromPort),
+			aws.Int64Value(ipPermission.ToPort),
+			cidrBlocks,
+			groupIDs,
+		)...)
+	}
+	return perms
+}
+
+// expandIpPermissions 将单条授权按协议和端口范围合并为 API 参数
+func expandIpPermissions(perms []securityGroupPermission) []*ec2.IpPermission {
+	var result []*ec2.IpPermission
+	index := make(map[string]*ec2.IpPermission)
+
+	for _, p := range perms {
+		k := fmt.Sprintf("%s_%d_%d", p.Protocol, p.FromPort, p.ToPort)
+		ipPermission, ok := index[k]
+		if !ok {
+			ipPermission = &ec2.IpPermission{IpProtocol: aws.String(p.Protocol)}
+			// 全部协议不需要端口范围
+			if p.Protocol != securityGroupProtocolAll {
+				ipPermission.FromPort = aws.Int64(p.FromPort)
+				ipPermission.ToPort = aws.Int64(p.ToPort)
+			}
+			index[k] = ipPermission
+			result = append(result, ipPermission)
+		}
+
+		if p.CidrIP != "" {
+			ipPermission.IpRanges = append(ipPermission.IpRanges, &ec2.IpRange{CidrIp: aws.String(p.CidrIP)})
+		}
+		if p.GroupID != "" {
+			ipPermission.UserIdGroupPairs = append(ipPermission.UserIdGroupPairs, &ec2.UserIdGroupPair{GroupId: aws.String(p.GroupID)})
+		}
+	}
+
+	return result
+}
+
+// flattenSecurityGroupRules 将 API 返回的授权转换为内联规则集合
+// 状态中原有的规则只要其全部授权仍然存在就原样保留，保持配置的书写方式；
+// 剩余的授权（如在控制台添加的规则）按协议和端口范围合并为新的规则
+func flattenSecurityGroupRules(ctx context.Context, perms []securityGroupPermission, prior types.Set) (types.Set, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	remaining := make(map[string]securityGroupPermission, len(perms))
+	for _, p := range perms {
+		remaining[p.key()] = p
+	}
+
+	var rules []SecurityGroupRuleModel
+	if !prior.IsNull() && !prior.IsUnknown() {
+		var priorRules []SecurityGroupRuleModel
+		diags.Append(prior.ElementsAs(ctx, &priorRules, false)...)
+		if diags.HasError() {
+			return prior, diags
+		}
+
+		for _, rule := range priorRules {
+			rulePerms, d := expandSecurityGroupRule(ctx, rule)
+			diags.Append(d...)
+			if len(rulePerms) == 0 {
+				continue
+			}
+
+			found := true
+			for _, p := range rulePerms {
+				if _, ok := remaining[p.key()]; !ok {
+					found = false
+					break
+				}
+			}
+			if !found {
+				continue
+			}
+
+			for _, p := range rulePerms {
+				delete(remaining, p.key())
+			}
+			rules = append(rules, rule)
+		}
+	}
+
+	// 剩余的授权按 API 返回的顺序合并，保证结果稳定
+	var leftover []securityGroupPermission
+	for _, p := range perms {
+		if _, ok := remaining[p.key()]; ok {
+			leftover = append(leftover, p)
+			delete(remaining, p.key())
+		}
+	}
+	for _, ipPermission := range expandIpPermissions(leftover) {
+		var cidrBlocks, groupIDs []string
+		for _, r := range ipPermission.IpRanges {
+			cidrBlocks = append(cidrBlocks, aws.StringValue(r.CidrIp))
+		}
+		for _, pair := range ipPermission.UserIdGroupPairs {
+			groupIDs = append(groupIDs, aws.StringValue(pair.GroupId))
+		}
+
+		rule := SecurityGroupRuleModel{
+			Protocol:       types.StringValue(aws.StringValue(ipPermission.IpProtocol)),
+			FromPort:       types.Int64Value(aws.Int64Value(ipPermission.FromPort)),
+			ToPort:         types.Int64Value(aws.Int64Value(ipPermission.ToPort)),
+			CidrBlocks:     types.SetNull(types.StringType),
+			SecurityGroups: types.SetNull(types.StringType),
+		}
+		if len(cidrBlocks) > 0 {
+			v, d := types.SetValueFrom(ctx, types.StringType, cidrBlocks)
+			diags.Append(d...)
+			rule.CidrBlocks = v
+		}
+		if len(groupIDs) > 0 {
+			v, d := types.SetValueFrom(ctx, types.StringType, groupIDs)
+			diags.Append(d...)
+			rule.SecurityGroups = v
+		}
+		rules = append(rules, rule)
+	}
+
+	if diags.HasError() {
+		return prior, diags
+	}
+
+	set, d := types.SetValueFrom(ctx, types.ObjectType{AttrTypes: securityGroupRuleAttrTypes}, rules)
+	diags.Append(d...)
+	return set, diags
+}
+
+// diffSecurityGroupPermissions 比较新旧授权，返回需要撤销和需要添加的授权
+func diffSecurityGroupPermissions(oldPerms, newPerms []securityGroupPermission) (revoke, authorize []securityGroupPermission) {
+	oldKeys := make(map[string]struct{}, len(oldPerms))
+	for _, p := range oldPerms {
+		oldKeys[p.key()] = struct{}{}
+	}
+	newKeys := make(map[string]struct{}, len(newPerms))
+	for _, p := range newPerms {
+		newKeys[p.key()] = struct{}{}
+	}
+
+	for _, p := range oldPerms {
+		if _, ok := newKeys[p.key()]; !ok {
+			revoke = append(revoke, p)
+		}
+	}
+	for _, p := range newPerms {
+		if _, ok := oldKeys[p.key()]; !ok {
+			authorize = append(authorize, p)
+		}
+	}
+	return revoke, authorize
+}
+
+// authorizeSecurityGroupPermissions 为安全组添加入方向或出方向授权
+func authorizeSecurityGroupPermissions(ctx context.Context, conn *ec2.EC2, groupID, ruleType string, perms []securityGroupPermission) error {
+	if len(perms) == 0 {
+		return nil
+	}
+
+	tflog.Debug(ctx, "添加安全组规则", map[string]interface{}{
+		"group_id": groupID,
+		"type":     ruleType,
+		"count":    len(perms),
+	})
+
+	var err error
+	if ruleType == securityGroupRuleTypeEgress {
+		_, err = conn.AuthorizeSecurityGroupEgressWithContext(ctx, &ec2.AuthorizeSecurityGroupEgressInput{
+			GroupId:       aws.String(groupID),
+			IpPermissions: expandIpPermissions(perms),
+		})
+	} else {
+		_, err = conn.AuthorizeSecurityGroupIngressWithContext(ctx, &ec2.AuthorizeSecurityGroupIngressInput{
+			GroupId:       aws.String(groupID),
+			IpPermissions: expandIpPermissions(perms),
+		})
+	}
+	return err
+}
+
+// revokeSecurityGroupPermissions 撤销安全组的入方向或出方向授权
+func revokeSecurityGroupPermissions(ctx context.Context, conn *ec2.EC2, groupID, ruleType string, perms []securityGroupPermission) error {
+	if len(perms) == 0 {
+		return nil
+	}
+
+	tflog.Debug(ctx, "撤销安全组规则", map[string]interface{}{
+		"group_id": groupID,
+		"type":     ruleType,
+		"count":    len(perms),
+	})
+
+	var err error
+	if ruleType == securityGroupRuleTypeEgress {
+		_, err = conn.RevokeSecurityGroupEgressWithContext(ctx, &ec2.RevokeSecurityGroupEgressInput{
+			GroupId:       aws.String(groupID),
+			IpPermissions: expandIpPermissions(perms),
+		})
+	} else {
+		_, err = conn.RevokeSecurityGroupIngressWithContext(ctx, &ec2.RevokeSecurityGroupIngressInput{
+			GroupId:       aws.String(groupID),
+			IpPermissions: expandIpPermissions(perms),
+		})
+	}
+	return err
+}
+
+// securityGroupPermissionsByType 返回安全组指定方向的单条授权
+func securityGroupPermissionsByType(group *ec2.SecurityGroup, ruleType string) []securityGroupPermission {
+	if ruleType == securityGroupRuleTypeEgress {
+		return flattenIpPermissions(group.IpPermissionsEgress)
+	}
+	return flattenIpPermissions(group.IpPermissions)
+}
@@ -0,0 +1,365 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package ec2
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/service/ec2"
+)
+
+// testSecurityGroupRule 构建内联规则，来源为空时对应属性为 null
+func testSecurityGroupRule(t *testing.T, protocol string, fromPort, toPort int64, cidrBlocks, groupIDs []string) SecurityGroupRuleModel {
+	t.Helper()
+	ctx := context.Background()
+
+	rule := SecurityGroupRuleModel{
+		Protocol:       types.StringValue(protocol),
+		FromPort:       types.Int64Value(fromPort),
+		ToPort:         types.Int64Value(toPort),
+		CidrBlocks:     types.SetNull(types.StringType),
+		SecurityGroups: types.SetNull(types.StringType),
+	}
+	if len(cidrBlocks) > 0 {
+		v, diags := types.SetValueFrom(ctx, types.StringType, cidrBlocks)
+		if diags.HasError() {
+			t.Fatalf("构建网段集合失败: %v", diags)
+		}
+		rule.CidrBlocks = v
+	}
+	if len(groupIDs) > 0 {
+		v, diags := types.SetValueFrom(ctx, types.StringType, groupIDs)
+		if diags.HasError() {
+			t.Fatalf("构建安全组集合失败: %v", diags)
+		}
+		rule.SecurityGroups = v
+	}
+	return rule
+}
+
+// testSecurityGroupRuleSet 构建内联规则集合
+func testSecurityGroupRuleSet(t *testing.T, rules ...SecurityGroupRuleModel) types.Set {
+	t.Helper()
+
+	set, diags := types.SetValueFrom(context.Background(), types.ObjectType{AttrTypes: securityGroupRuleAttrTypes}, rules)
+	if diags.HasError() {
+		t.Fatalf("构建规则集合失败: %v", diags)
+	}
+	return set
+}
+
+// TestSecurityGroupRuleHash 测试规则哈希只取决于规则内容，与来源的书写顺序无关
+func TestSecurityGroupRuleHash(t *testing.T) {
+	base := newSecurityGroupPermissions("tcp", 80, 80, []string{"10.0.0.0/16", "10.1.0.0/16"}, nil)
+
+	tests := []struct {
+		name     string
+		ruleType string
+		groupID  string
+		perms    []securityGroupPermission
+		wantSame bool
+	}{
+		{
+			name:     "reordered",
+			ruleType: securityGroupRuleTypeIngress,
+			groupID:  "sg-12345678",
+			perms:    newSecurityGroupPermissions("tcp", 80, 80, []string{"10.1.0.0/16", "10.0.0.0/16"}, nil),
+			wantSame: true,
+		},
+		{
+			name:     "protocol number",
+			ruleType: securityGroupRuleTypeIngress,
+			groupID:  "sg-12345678",
+			perms:    newSecurityGroupPermissions("6", 80, 80, []string{"10.0.0.0/16", "10.1.0.0/16"}, nil),
+			wantSame: true,
+		},
+		{
+			name:     "different type",
+			ruleType: securityGroupRuleTypeEgress,
+			groupID:  "sg-12345678",
+			perms:    base,
+			wantSame: false,
+		},
+		{
+			name:     "different group",
+			ruleType: securityGroupRuleTypeIngress,
+			groupID:  "sg-87654321",
+			perms:    base,
+			wantSame: false,
+		},
+		{
+			name:     "different port",
+			ruleType: securityGroupRuleTypeIngress,
+			groupID:  "sg-12345678",
+			perms:    newSecurityGroupPermissions("tcp", 443, 443, []string{"10.0.0.0/16", "10.1.0.0/16"}, nil),
+			wantSame: false,
+		},
+	}
+
+	want := securityGroupRuleHash(securityGroupRuleTypeIngress, "sg-12345678", base)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := securityGroupRuleHash(tt.ruleType, tt.groupID, tt.perms)
+			if (got == want) != tt.wantSame {
+				t.Errorf("哈希比较结果应为 %t，得到 %s 和 %s", tt.wantSame, got, want)
+			}
+		})
+	}
+}
+
+// TestParseSecurityGroupRuleImportID 测试导入 ID 的解析和错误处理
+func TestParseSecurityGroupRuleImportID(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		id          string
+		wantErr     bool
+		wantType    string
+		wantCidrs   []string
+		wantSource  string
+		wantFromTo  [2]int64
+		wantGroupID string
+	}{
+		{
+			name:        "cidr blocks",
+			id:          "sg-12345678_ingress_tcp_80_80_10.0.0.0/16_10.1.0.0/16",
+			wantType:    securityGroupRuleTypeIngress,
+			wantCidrs:   []string{"10.0.0.0/16", "10.1.0.0/16"},
+			wantFromTo:  [2]int64{80, 80},
+			wantGroupID: "sg-12345678",
+		},
+		{
+			name:        "source security group",
+			id:          "sg-12345678_egress_-1_0_0_sg-87654321",
+			wantType:    securityGroupRuleTypeEgress,
+			wantSource:  "sg-87654321",
+			wantFromTo:  [2]int64{0, 0},
+			wantGroupID: "sg-12345678",
+		},
+		{
+			name:    "too few parts",
+			id:      "sg-12345678_ingress_tcp_80_80",
+			wantErr: true,
+		},
+		{
+			name:    "invalid type",
+			id:      "sg-12345678_inbound_tcp_80_80_10.0.0.0/16",
+			wantErr: true,
+		},
+		{
+			name:    "invalid from port",
+			id:      "sg-12345678_ingress_tcp_http_80_10.0.0.0/16",
+			wantErr: true,
+		},
+		{
+			name:    "invalid to port",
+			id:      "sg-12345678_ingress_tcp_80_http_10.0.0.0/16",
+			wantErr: true,
+		},
+		{
+			name:    "multiple source security groups",
+			id:      "sg-12345678_ingress_tcp_80_80_sg-1_sg-2",
+			wantErr: true,
+		},
+		{
+			name:    "mixed sources",
+			id:      "sg-12345678_ingress_tcp_80_80_10.0.0.0/16_sg-87654321",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, err := parseSecurityGroupRuleImportID(ctx, tt.id)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("解析 %s 应返回错误", tt.id)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("解析 %s 失败: %v", tt.id, err)
+			}
+
+			if model.SecurityGroupID.ValueString() != tt.wantGroupID {
+				t.Errorf("security_group_id 应为 %s，得到 %s", tt.wantGroupID, model.SecurityGroupID)
+			}
+			if model.Type.ValueString() != tt.wantType {
+				t.Errorf("type 应为 %s，得到 %s", tt.wantType, model.Type)
+			}
+			if model.FromPort.ValueInt64() != tt.wantFromTo[0] || model.ToPort.ValueInt64() != tt.wantFromTo[1] {
+				t.Errorf("端口范围应为 %v，得到 %s-%s", tt.wantFromTo, model.FromPort, model.ToPort)
+			}
+			if model.SourceSecurityGroupID.ValueString() != tt.wantSource {
+				t.Errorf("source_security_group_id 应为 %q，得到 %s", tt.wantSource, model.SourceSecurityGroupID)
+			}
+
+			var cidrs []string
+			if !model.CidrBlocks.IsNull() {
+				if diags := model.CidrBlocks.ElementsAs(ctx, &cidrs, false); diags.HasError() {
+					t.Fatalf("读取网段失败: %v", diags)
+				}
+			}
+			if len(cidrs) != len(tt.wantCidrs) {
+				t.Fatalf("网段应为 %v，得到 %v", tt.wantCidrs, cidrs)
+			}
+			want := make(map[string]struct{}, len(tt.wantCidrs))
+			for _, cidr := range tt.wantCidrs {
+				want[cidr] = struct{}{}
+			}
+			for _, cidr := range cidrs {
+				if _, ok := want[cidr]; !ok {
+					t.Errorf("网段应为 %v，得到 %v", tt.wantCidrs, cidrs)
+				}
+			}
+		})
+	}
+}
+
+// TestFlattenSecurityGroupRules 测试状态中的规则在 API 返回顺序或合并方式不同时保持不变，
+// 规则外的授权合并为新规则，缺少授权的规则被移除
+func TestFlattenSecurityGroupRules(t *testing.T) {
+	ctx := context.Background()
+
+	web := testSecurityGroupRule(t, "tcp", 80, 80, []string{"10.0.0.0/16", "10.1.0.0/16"}, nil)
+	ssh := testSecurityGroupRule(t, "tcp", 22, 22, nil, []string{"sg-87654321"})
+	all := testSecurityGroupRule(t, "all", 0, 65535, []string{"0.0.0.0/0"}, nil)
+
+	tests := []struct {
+		name          string
+		ipPermissions []*ec2.IpPermission
+		prior         types.Set
+		want          types.Set
+	}{
+		{
+			name: "reordered",
+			ipPermissions: []*ec2.IpPermission{
+				{IpProtocol: aws.String("-1"), IpRanges: []*ec2.IpRange{{CidrIp: aws.String("0.0.0.0/0")}}},
+				{IpProtocol: aws.String("tcp"), FromPort: aws.Int64(22), ToPort: aws.Int64(22), UserIdGroupPairs: []*ec2.UserIdGroupPair{{GroupId: aws.String("sg-87654321")}}},
+				{IpProtocol: aws.String("tcp"), FromPort: aws.Int64(80), ToPort: aws.Int64(80), IpRanges: []*ec2.IpRange{{CidrIp: aws.String("10.1.0.0/16")}}},
+				{IpProtocol: aws.String("tcp"), FromPort: aws.Int64(80), ToPort: aws.Int64(80), IpRanges: []*ec2.IpRange{{CidrIp: aws.String("10.0.0.0/16")}}},
+			},
+			prior: testSecurityGroupRuleSet(t, web, ssh, all),
+			want:  testSecurityGroupRuleSet(t, all, ssh, web),
+		},
+		{
+			name: "rule added outside terraform",
+			ipPermissions: []*ec2.IpPermission{
+				{IpProtocol: aws.String("tcp"), FromPort: aws.Int64(80), ToPort: aws.Int64(80), IpRanges: []*ec2.IpRange{{CidrIp: aws.String("10.0.0.0/16")}, {CidrIp: aws.String("10.1.0.0/16")}}},
+				{IpProtocol: aws.String("tcp"), FromPort: aws.Int64(443), ToPort: aws.Int64(443), IpRanges: []*ec2.IpRange{{CidrIp: aws.String("10.2.0.0/16")}}},
+				{IpProtocol: aws.String("tcp"), FromPort: aws.Int64(443), ToPort: aws.Int64(443), IpRanges: []*ec2.IpRange{{CidrIp: aws.String("10.3.0.0/16")}}},
+			},
+			prior: testSecurityGroupRuleSet(t, web),
+			want:  testSecurityGroupRuleSet(t, web, testSecurityGroupRule(t, "tcp", 443, 443, []string{"10.2.0.0/16", "10.3.0.0/16"}, nil)),
+		},
+		{
+			name: "rule partially removed outside terraform",
+			ipPermissions: []*ec2.IpPermission{
+				{IpProtocol: aws.String("tcp"), FromPort: aws.Int64(80), ToPort: aws.Int64(80), IpRanges: []*ec2.IpRange{{CidrIp: aws.String("10.0.0.0/16")}}},
+			},
+			prior: testSecurityGroupRuleSet(t, web),
+			want:  testSecurityGroupRuleSet(t, testSecurityGroupRule(t, "tcp", 80, 80, []string{"10.0.0.0/16"}, nil)),
+		},
+		{
+			name:          "no rules",
+			ipPermissions: nil,
+			prior:         testSecurityGroupRuleSet(t, web),
+			want:          testSecurityGroupRuleSet(t),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, diags := flattenSecurityGroupRules(ctx, flattenIpPermissions(tt.ipPermissions), tt.prior)
+			if diags.HasError() {
+				t.Fatalf("转换规则失败: %v", diags)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("规则应为 %s，得到 %s", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestDiffSecurityGroupPermissions 测试只撤销和添加发生变化的授权，顺序不同的相同规则没有差异
+func TestDiffSecurityGroupPermissions(t *testing.T) {
+	ctx := context.Background()
+
+	expand := func(t *testing.T, rules ...SecurityGroupRuleModel) []securityGroupPermission {
+		t.Helper()
+		perms, diags := expandSecurityGroupRules(ctx, testSecurityGroupRuleSet(t, rules...))
+		if diags.HasError() {
+			t.Fatalf("拆分规则失败: %v", diags)
+		}
+		return perms
+	}
+
+	web := testSecurityGroupRule(t, "tcp", 80, 80, []string{"10.0.0.0/16", "10.1.0.0/16"}, nil)
+	ssh := testSecurityGroupRule(t, "tcp", 22, 22, nil, []string{"sg-87654321"})
+
+	tests := []struct {
+		name          string
+		oldPerms      []securityGroupPermission
+		newPerms      []securityGroupPermission
+		wantRevoke    []string
+		wantAuthorize []string
+	}{
+		{
+			name:     "reordered",
+			oldPerms: expand(t, web, ssh),
+			newPerms: expand(t, ssh, testSecurityGroupRule(t, "tcp", 80, 80, []string{"10.1.0.0/16", "10.0.0.0/16"}, nil)),
+		},
+		{
+			name:     "split rule",
+			oldPerms: expand(t, web),
+			newPerms: expand(t,
+				testSecurityGroupRule(t, "tcp", 80, 80, []string{"10.0.0.0/16"}, nil),
+				testSecurityGroupRule(t, "tcp", 80, 80, []string{"10.1.0.0/16"}, nil),
+			),
+		},
+		{
+			name:     "protocol alias",
+			oldPerms: expand(t, testSecurityGroupRule(t, "-1", 0, 0, []string{"0.0.0.0/0"}, nil)),
+			newPerms: expand(t, testSecurityGroupRule(t, "all", 0, 65535, []string{"0.0.0.0/0"}, nil)),
+		},
+		{
+			name:          "changed source",
+			oldPerms:      expand(t, web, ssh),
+			newPerms:      expand(t, ssh, testSecurityGroupRule(t, "tcp", 80, 80, []string{"10.0.0.0/16", "10.2.0.0/16"}, nil)),
+			wantRevoke:    []string{"tcp_80_80_10.1.0.0/16_"},
+			wantAuthorize: []string{"tcp_80_80_10.2.0.0/16_"},
+		},
+		{
+			name:       "removed rule",
+			oldPerms:   expand(t, web, ssh),
+			newPerms:   expand(t, web),
+			wantRevoke: []string{"tcp_22_22__sg-87654321"},
+		},
+	}
+
+	keys := func(perms []securityGroupPermission) []string {
+		var result []string
+		for _, p := range perms {
+			result = append(result, p.key())
+		}
+		return result
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			revoke, authorize := diffSecurityGroupPermissions(tt.oldPerms, tt.newPerms)
+			if got := keys(revoke); !slices.Equal(got, tt.wantRevoke) {
+				t.Errorf("撤销的授权应为 %v，得到 %v", tt.wantRevoke, got)
+			}
+			if got := keys(authorize); !slices.Equal(got, tt.wantAuthorize) {
+				t.Errorf("添加的授权应为 %v，得到 %v", tt.wantAuthorize, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package ec2_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/mulei1288/terraform-provider-bingocloud/internal/acctest"
+)
+
+// testAccSecurityGroupConfig 生成安全组资源的测试配置，ingressCidr 为 SSH 规则允许的网段
+func testAccSecurityGroupConfig(name, ingressCidr string) string {
+	return acctest.ProviderConfig() + fmt.Sprintf(`
+resource "bingocloud_vpc" "test" {
+  cidr_block = "10.30.0.0/16"
+
+  tags = {
+    Name = %[1]q
+  }
+}
+
+resource "bingocloud_security_group" "test" {
+  name   = %[1]q
+  vpc_id = bingocloud_vpc.test.id
+
+  ingress {
+    protocol    = "tcp"
+    from_port   = 22
+    to_port     = 22
+    cidr_blocks = [%[2]q]
+  }
+
+  ingress {
+    protocol    = "icmp"
+    from_port   = -1
+    to_port     = -1
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  egress {
+    protocol    = "-1"
+    from_port   = 0
+    to_port     = 0
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  tags = {
+    Name = %[1]q
+  }
+}
+`, name, ingressCidr)
+}
+
+// TestAccSecurityGroupResource_basic 测试安全组资源的创建、内联规则更新和导入
+func TestAccSecurityGroupResource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// 创建和读取测试
+			{
+				Config: testAccSecurityGroupConfig("test-sg", "10.30.0.0/16"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("bingocloud_security_group.test", "name", "test-sg"),
+					resource.TestCheckResourceAttr("bingocloud_security_group.test", "description", "Managed by Terraform"),
+					resource.TestCheckResourceAttrPair("bingocloud_security_group.test", "vpc_id", "bingocloud_vpc.test", "id"),
+					resource.TestCheckResourceAttr("bingocloud_security_group.test", "ingress.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs("bingocloud_security_group.test", "ingress.*", map[string]string{
+						"protocol":      "tcp",
+						"from_port":     "22",
+						"to_port":       "22",
+						"cidr_blocks.0": "10.30.0.0/16",
+					}),
+					resource.TestCheckResourceAttr("bingocloud_security_group.test", "egress.#", "1"),
+					resource.TestCheckResourceAttrSet("bingocloud_security_group.test", "id"),
+				),
+			},
+			// 原地修改内联规则
+			{
+				Config: testAccSecurityGroupConfig("test-sg", "192.168.0.0/24"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("bingocloud_security_group.test", "ingress.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs("bingocloud_security_group.test", "ingress.*", map[string]string{
+						"protocol":      "tcp",
+						"from_port":     "22",
+						"to_port":       "22",
+						"cidr_blocks.0": "192.168.0.0/24",
+					}),
+				),
+			},
+			// 导入状态测试
+			{
+				ResourceName:      "bingocloud_security_group.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
@@ -17,6 +17,14 @@ type ServicePackage struct{}
 func (p *ServicePackage) FrameworkResources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewInstanceResource,
+		NewInstanceGroupResource,
+		NewVpcResource,
+		NewSubnetResource,
+		NewSecurityGroupResource,
+		NewSecurityGroupRuleResource,
+		NewKeyPairResource,
+		NewEipResource,
+		NewEipAssociationResource,
 		// 未来可以添加更多资源
 		// NewVolumeResource,
 		// NewSnapshotResource,
@@ -0,0 +1,485 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package ec2
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/mulei1288/terraform-provider-bingocloud/internal/conns"
+	tftags "github.com/mulei1288/terraform-provider-bingocloud/internal/tags"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/awserr"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/service/ec2"
+)
+
+// 确保实现了必需的接口
+var _ resource.Resource = &SubnetResource{}
+var _ resource.ResourceWithImportState = &SubnetResource{}
+var _ resource.ResourceWithModifyPlan = &SubnetResource{}
+var _ resource.ResourceWithValidateConfig = &SubnetResource{}
+
+// 默认超时时间
+const (
+	subnetCreateTimeout = 10 * time.Minute
+	subnetUpdateTimeout = 10 * time.Minute
+	subnetDeleteTimeout = 20 * time.Minute
+)
+
+// errCodeInvalidSubnetIDNotFound 子网不存在的错误码
+const errCodeInvalidSubnetIDNotFound = "InvalidSubnetID.NotFound"
+
+// SubnetResource 定义子网资源实现
+type SubnetResource struct {
+	client *conns.BingoCloudClient
+}
+
+// SubnetResourceModel 描述子网资源数据模型
+type SubnetResourceModel struct {
+	// 必需参数
+	VpcID     types.String `tfsdk:"vpc_id"`
+	CidrBlock types.String `tfsdk:"cidr_block"`
+
+	// 可选参数
+	AvailabilityZone    types.String `tfsdk:"availability_zone"`
+	MapPublicIpOnLaunch types.Bool   `tfsdk:"map_public_ip_on_launch"`
+	Tags                types.Map    `tfsdk:"tags"`
+
+	// 计算属性
+	ID      types.String `tfsdk:"id"`
+	TagsAll types.Map    `tfsdk:"tags_all"`
+
+	// 超时配置
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// NewSubnetResource 创建新的子网资源
+func NewSubnetResource() resource.Resource {
+	return &SubnetResource{}
+}
+
+// Metadata 返回资源类型名称
+func (r *SubnetResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_subnet"
+}
+
+// Configure 配置资源，接收 Provider 传递的客户端
+func (r *SubnetResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*conns.BingoCloudClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"意外的资源配置类型",
+			fmt.Sprintf("期望 *conns.BingoCloudClient，得到: %T。请向 provider 开发者报告此问题。", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+// Schema 定义资源的属性架构
+func (r *SubnetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "管理 BingoCloud VPC 子网",
+
+		Attributes: map[string]schema.Attribute{
+			// 必需参数
+			"vpc_id": schema.StringAttribute{
+				MarkdownDescription: "子网所属的 VPC ID",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"cidr_block": schema.StringAttribute{
+				MarkdownDescription: "子网的 IPv4 网段，必须在 VPC 网段之内（如 10.0.1.0/24），修改时重建子网",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			// 可选参数
+			"availability_zone": schema.StringAttribute{
+				MarkdownDescription: "子网所在的可用区，未配置时由平台选择，修改时重建子网",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"map_public_ip_on_launch": schema.BoolAttribute{
+				MarkdownDescription: "在该子网中启动的实例是否自动分配公网 IP，默认 false",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"tags": tftags.TagsAttribute(),
+
+			// 计算属性（只读）
+			"id": schema.StringAttribute{
+				MarkdownDescription: "子网 ID",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tags_all": tftags.TagsAllAttribute(),
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// Create 创建子网并等待其可用
+func (r *SubnetResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan SubnetResourceModel
+
+	// 读取 Terraform 计划数据
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, subnetCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	conn := r.client.EC2Client()
+
+	input := &ec2.CreateSubnetInput{
+		VpcId:     aws.String(plan.VpcID.ValueString()),
+		CidrBlock: aws.String(plan.CidrBlock.ValueString()),
+	}
+	if !plan.AvailabilityZone.IsNull() && !plan.AvailabilityZone.IsUnknown() {
+		input.AvailabilityZone = aws.String(plan.AvailabilityZone.ValueString())
+	}
+
+	tflog.Debug(ctx, "创建 BingoCloud 子网", map[string]interface{}{
+		"vpc_id":            plan.VpcID.ValueString(),
+		"cidr_block":        plan.CidrBlock.ValueString(),
+		"availability_zone": plan.AvailabilityZone.ValueString(),
+	})
+
+	result, err := conn.CreateSubnetWithContext(ctx, input)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"创建子网失败",
+			"无法创建子网: "+err.Error(),
+		)
+		return
+	}
+	if result.Subnet == nil || result.Subnet.SubnetId == nil {
+		resp.Diagnostics.AddError(
+			"创建子网失败",
+			"API 返回空的子网信息",
+		)
+		return
+	}
+
+	subnetID := aws.StringValue(result.Subnet.SubnetId)
+	plan.ID = types.StringValue(subnetID)
+
+	// 立即保存子网 ID，后续步骤失败时资源会被标记为 tainted
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), plan.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("timeouts"), plan.Timeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := conn.WaitUntilSubnetAvailableWithContext(ctx, &ec2.DescribeSubnetsInput{
+		SubnetIds: []*string{aws.String(subnetID)},
+	}, waiterOptions(createTimeout)...); err != nil {
+		resp.Diagnostics.AddError(
+			"等待子网可用失败",
+			"子网 "+subnetID+" 创建成功但未能进入可用状态: "+waitErrorDetail(ctx, "创建", createTimeout, err, subnetLastState(conn, subnetID)),
+		)
+		return
+	}
+
+	// 标签：创建后通过 CreateTags 添加
+	tags, diags := tagsMapValue(ctx, plan.TagsAll)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if err := updateTags(ctx, conn, subnetID, nil, tags); err != nil {
+		resp.Diagnostics.AddError(
+			"设置子网标签失败",
+			"无法为子网 "+subnetID+" 设置标签: "+err.Error(),
+		)
+		return
+	}
+
+	// 自动分配公网 IP：API 默认不分配，只在启用时修改
+	if plan.MapPublicIpOnLaunch.ValueBool() {
+		if err := modifySubnetMapPublicIpOnLaunch(ctx, conn, subnetID, true); err != nil {
+			resp.Diagnostics.AddError(
+				"修改子网属性失败",
+				"无法为子网 "+subnetID+" 启用自动分配公网 IP: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	subnet, err := findSubnetByID(ctx, conn, subnetID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"读取子网详情失败",
+			"子网创建成功但无法读取详细信息: "+err.Error(),
+		)
+		return
+	}
+	plan.AvailabilityZone = types.StringValue(aws.StringValue(subnet.AvailabilityZone))
+
+	tflog.Trace(ctx, "创建子网成功")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read 读取子网状态
+func (r *SubnetResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state SubnetResourceModel
+
+	// 读取 Terraform 状态数据
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	subnet, err := findSubnetByID(ctx, r.client.EC2Client(), state.ID.ValueString())
+	if err != nil {
+		if isErrorCode(err, errCodeInvalidSubnetIDNotFound) {
+			// 子网不存在，从状态中移除
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"读取子网失败",
+			"无法读取子网 "+state.ID.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	state.VpcID = types.StringValue(aws.StringValue(subnet.VpcId))
+	state.CidrBlock = types.StringValue(aws.StringValue(subnet.CidrBlock))
+	state.AvailabilityZone = types.StringValue(aws.StringValue(subnet.AvailabilityZone))
+	state.MapPublicIpOnLaunch = types.BoolValue(aws.BoolValue(subnet.MapPublicIpOnLaunch))
+
+	// 标签：按 provider 的默认标签和忽略标签配置拆分到 tags 和 tags_all
+	tagsValue, tagsAllValue, diags := tftags.Flatten(ctx, tagsToMap(subnet.Tags), state.Tags, r.client.DefaultTagsConfig, r.client.IgnoreTagsConfig)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	state.Tags = tagsValue
+	state.TagsAll = tagsAllValue
+
+	// 保存更新后的状态
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update 更新子网的自动分配公网 IP 设置和标签
+func (r *SubnetResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state SubnetResourceModel
+
+	// 读取计划数据和当前状态
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, subnetUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	conn := r.client.EC2Client()
+	subnetID := state.ID.ValueString()
+
+	if !plan.MapPublicIpOnLaunch.Equal(state.MapPublicIpOnLaunch) {
+		if err := modifySubnetMapPublicIpOnLaunch(ctx, conn, subnetID, plan.MapPublicIpOnLaunch.ValueBool()); err != nil {
+			resp.Diagnostics.AddError(
+				"修改子网属性失败",
+				"无法修改子网 "+subnetID+" 的自动分配公网 IP 设置: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	// 标签：按包含默认标签的 tags_all 比较
+	if !plan.TagsAll.Equal(state.TagsAll) {
+		oldTags, diags := tagsMapValue(ctx, state.TagsAll)
+		resp.Diagnostics.Append(diags...)
+		newTags, diags := tagsMapValue(ctx, plan.TagsAll)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if err := updateTags(ctx, conn, subnetID, oldTags, newTags); err != nil {
+			resp.Diagnostics.AddError(
+				"更新子网标签失败",
+				"无法更新子网 "+subnetID+" 的标签: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	tflog.Trace(ctx, "更新子网成功")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete 删除子网并等待删除完成
+// 子网中的实例正在终止时 API 返回 DependencyViolation，等待实例终止后重试
+func (r *SubnetResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state SubnetResourceModel
+
+	// 读取 Terraform 状态数据
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, subnetDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	conn := r.client.EC2Client()
+	subnetID := state.ID.ValueString()
+
+	tflog.Debug(ctx, "删除 BingoCloud 子网", map[string]interface{}{
+		"subnet_id": subnetID,
+	})
+
+	err := retryWhenErrorCode(ctx, errCodeDependencyViolation, func() error {
+		_, err := conn.DeleteSubnetWithContext(ctx, &ec2.DeleteSubnetInput{
+			SubnetId: aws.String(subnetID),
+		})
+		return err
+	})
+	if isErrorCode(err, errCodeInvalidSubnetIDNotFound) {
+		return
+	}
+	if err == nil {
+		err = waitSubnetDeleted(ctx, conn, subnetID, deleteTimeout)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"删除子网失败",
+			"无法删除子网 "+subnetID+": "+waitErrorDetail(ctx, "删除", deleteTimeout, err, subnetLastState(conn, subnetID)),
+		)
+		return
+	}
+
+	tflog.Trace(ctx, "删除子网成功")
+}
+
+// ValidateConfig 校验配置：cidr_block 必须是有效的网段
+func (r *SubnetResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config SubnetResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateCidrBlock(config.CidrBlock, path.Root("cidr_block"))...)
+}
+
+// ModifyPlan 调整计划：计算 tags_all
+func (r *SubnetResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// 销毁时无需调整
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	// 合并 provider 的默认标签
+	if r.client != nil {
+		resp.Diagnostics.Append(tftags.ModifyPlan(ctx, r.client.DefaultTagsConfig, r.client.IgnoreTagsConfig, &resp.Plan)...)
+	}
+}
+
+// ImportState 支持通过子网 ID 导入资源
+func (r *SubnetResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// findSubnetByID 根据子网 ID 查询子网，子网不存在时返回 InvalidSubnetID.NotFound 错误
+func findSubnetByID(ctx context.Context, conn *ec2.EC2, id string) (*ec2.Subnet, error) {
+	result, err := conn.DescribeSubnetsWithContext(ctx, &ec2.DescribeSubnetsInput{
+		SubnetIds: []*string{aws.String(id)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Subnets) == 0 || result.Subnets[0] == nil {
+		return nil, awserr.New(errCodeInvalidSubnetIDNotFound, "子网 "+id+" 不存在", nil)
+	}
+
+	return result.Subnets[0], nil
+}
+
+// subnetLastState 返回查询子网最后状态的函数，查询失败时返回 unknown
+func subnetLastState(conn *ec2.EC2, id string) func(context.Context) string {
+	return func(ctx context.Context) string {
+		subnet, err := findSubnetByID(ctx, conn, id)
+		if err != nil {
+			if isErrorCode(err, errCodeInvalidSubnetIDNotFound) {
+				return "deleted"
+			}
+			return "unknown"
+		}
+		return aws.StringValue(subnet.State)
+	}
+}
+
+// modifySubnetMapPublicIpOnLaunch 修改子网的自动分配公网 IP 设置
+func modifySubnetMapPublicIpOnLaunch(ctx context.Context, conn *ec2.EC2, id string, value bool) error {
+	tflog.Debug(ctx, "修改子网自动分配公网 IP 设置", map[string]interface{}{
+		"subnet_id": id,
+		"value":     value,
+	})
+
+	_, err := conn.ModifySubnetAttributeWithContext(ctx, &ec2.ModifySubnetAttributeInput{
+		SubnetId:            aws.String(id),
+		MapPublicIpOnLaunch: &ec2.AttributeBooleanValue{Value: aws.Bool(value)},
+	})
+	return err
+}
@@ -0,0 +1,69 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package ec2_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/mulei1288/terraform-provider-bingocloud/internal/acctest"
+)
+
+// testAccSubnetConfig 生成子网资源的测试配置，子网创建在新建的 VPC 中
+func testAccSubnetConfig(name string, mapPublicIpOnLaunch bool) string {
+	return acctest.ProviderConfig() + fmt.Sprintf(`
+resource "bingocloud_vpc" "test" {
+  cidr_block = "10.20.0.0/16"
+
+  tags = {
+    Name = %[1]q
+  }
+}
+
+resource "bingocloud_subnet" "test" {
+  vpc_id                  = bingocloud_vpc.test.id
+  cidr_block              = "10.20.1.0/24"
+  map_public_ip_on_launch = %[2]t
+
+  tags = {
+    Name = %[1]q
+  }
+}
+`, name, mapPublicIpOnLaunch)
+}
+
+// TestAccSubnetResource_basic 测试子网资源的创建、原地更新和导入
+func TestAccSubnetResource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// 创建和读取测试
+			{
+				Config: testAccSubnetConfig("test-subnet", false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("bingocloud_subnet.test", "vpc_id", "bingocloud_vpc.test", "id"),
+					resource.TestCheckResourceAttr("bingocloud_subnet.test", "cidr_block", "10.20.1.0/24"),
+					resource.TestCheckResourceAttr("bingocloud_subnet.test", "map_public_ip_on_launch", "false"),
+					resource.TestCheckResourceAttrSet("bingocloud_subnet.test", "availability_zone"),
+					resource.TestCheckResourceAttrSet("bingocloud_subnet.test", "id"),
+				),
+			},
+			// 原地修改自动分配公网 IP
+			{
+				Config: testAccSubnetConfig("test-subnet", true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("bingocloud_subnet.test", "map_public_ip_on_launch", "true"),
+				),
+			},
+			// 导入状态测试
+			{
+				ResourceName:      "bingocloud_subnet.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
@@ -0,0 +1,23 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package ec2
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// tagsMapValue 将 tags 或 tags_all 属性转换为映射，空值或未知值返回空映射
+func tagsMapValue(ctx context.Context, tags types.Map) (map[string]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	result := make(map[string]string)
+	if !tags.IsNull() && !tags.IsUnknown() {
+		diags.Append(tags.ElementsAs(ctx, &result, false)...)
+	}
+
+	return result, diags
+}
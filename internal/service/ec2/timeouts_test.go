@@ -0,0 +1,432 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package ec2
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/mulei1288/terraform-provider-bingocloud/internal/conns"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/credentials"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/session"
+)
+
+const (
+	// testTimeout 测试中 timeouts 块配置的超时时间
+	testTimeout = 100 * time.Millisecond
+	// testTimeoutLimit 操作需要在这个时间内返回，否则说明没有使用 timeouts 中配置的超时时间
+	testTimeoutLimit = 10 * time.Second
+)
+
+// testTimeoutErrorResponse endpoint 对第一个请求之后的请求返回的错误响应
+const testTimeoutErrorResponse = `<Response><Errors><Error><Code>InvalidParameterValue</Code><Message>test</Message></Error></Errors><RequestID>test</RequestID></Response>`
+
+// testTimeoutCase 资源超时测试用例：state 为空时执行 Create，plan 为空时执行 Delete，否则执行 Update
+type testTimeoutCase struct {
+	name  string
+	state map[string]any
+	plan  map[string]any
+}
+
+// testTimeoutClient 返回连接到测试 endpoint 的客户端
+// endpoint 不响应第一个请求，直到请求被取消；之后的请求（如超时后查询最后状态）直接返回错误
+func testTimeoutClient(t *testing.T) *conns.BingoCloudClient {
+	t.Helper()
+
+	release := make(chan struct{})
+	var once sync.Once
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		first := false
+		once.Do(func() { first = true })
+		if first {
+			select {
+			case <-r.Context().Done():
+			case <-release:
+			}
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(testTimeoutErrorResponse))
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+
+	sess, err := session.NewSession(&aws.Config{
+		Endpoint:    aws.String(server.URL),
+		Region:      aws.String("test"),
+		Credentials: credentials.NewStaticCredentials("test", "test", ""),
+		MaxRetries:  aws.Int(0),
+	})
+	if err != nil {
+		t.Fatalf("创建 session 失败: %v", err)
+	}
+
+	return &conns.BingoCloudClient{
+		Config:  sess.Config,
+		Session: sess,
+	}
+}
+
+// testResourceValue 按 schema 构建资源数据，timeouts 块中的所有超时时间都设置为 testTimeout
+func testResourceValue(ctx context.Context, t *testing.T, s schema.Schema, attrs map[string]any) tftypes.Value {
+	t.Helper()
+
+	state := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+
+	var diags diag.Diagnostics
+	for name, value := range attrs {
+		diags.Append(state.SetAttribute(ctx, path.Root(name), value)...)
+	}
+	block, ok := s.Blocks["timeouts"].(schema.SingleNestedBlock)
+	if !ok {
+		t.Fatal("资源没有定义 timeouts 块")
+	}
+	for name := range block.Attributes {
+		diags.Append(state.SetAttribute(ctx, path.Root("timeouts").AtName(name), testTimeout.String())...)
+	}
+	if diags.HasError() {
+		t.Fatalf("构建资源数据失败: %v", diags)
+	}
+
+	return state.Raw
+}
+
+// testResourceTimeouts 对每个用例执行资源操作，确认操作在 timeouts 中配置的时间后因超时返回
+func testResourceTimeouts(t *testing.T, newResource func() resource.Resource, cases []testTimeoutCase) {
+	t.Helper()
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			r := newResource()
+			var configureResp resource.ConfigureResponse
+			r.(resource.ResourceWithConfigure).Configure(ctx, resource.ConfigureRequest{ProviderData: testTimeoutClient(t)}, &configureResp)
+			if configureResp.Diagnostics.HasError() {
+				t.Fatalf("配置资源失败: %v", configureResp.Diagnostics)
+			}
+
+			var schemaResp resource.SchemaResponse
+			r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+			s := schemaResp.Schema
+
+			nullValue := tftypes.NewValue(s.Type().TerraformType(ctx), nil)
+			stateValue, planValue := nullValue, nullValue
+			if tt.state != nil {
+				stateValue = testResourceValue(ctx, t, s, tt.state)
+			}
+			if tt.plan != nil {
+				planValue = testResourceValue(ctx, t, s, tt.plan)
+			}
+
+			done := make(chan diag.Diagnostics, 1)
+			start := time.Now()
+			go func() {
+				state := tfsdk.State{Schema: s, Raw: stateValue}
+				plan := tfsdk.Plan{Schema: s, Raw: planValue}
+				config := tfsdk.Config{Schema: s, Raw: planValue}
+
+				switch {
+				case tt.state == nil:
+					resp := resource.CreateResponse{State: tfsdk.State{Schema: s, Raw: nullValue}}
+					r.Create(ctx, resource.CreateRequest{Plan: plan, Config: config}, &resp)
+					done <- resp.Diagnostics
+				case tt.plan == nil:
+					resp := resource.DeleteResponse{State: state}
+					r.Delete(ctx, resource.DeleteRequest{State: state}, &resp)
+					done <- resp.Diagnostics
+				default:
+					resp := resource.UpdateResponse{State: state}
+					r.Update(ctx, resource.UpdateRequest{State: state, Plan: plan, Config: config}, &resp)
+					done <- resp.Diagnostics
+				}
+			}()
+
+			select {
+			case diags := <-done:
+				if elapsed := time.Since(start); elapsed < testTimeout {
+					t.Fatalf("操作在配置的超时时间 %s 之前返回（%s）: %v", testTimeout, elapsed, diags)
+				}
+				if !diags.HasError() {
+					t.Fatal("请求超时后操作应返回错误")
+				}
+			case <-time.After(testTimeoutLimit):
+				t.Fatalf("操作在 %s 内没有返回，没有使用 timeouts 中配置的超时时间 %s", testTimeoutLimit, testTimeout)
+			}
+		})
+	}
+}
+
+// TestInstanceResourceTimeouts 测试虚拟机的创建、更新和删除使用 timeouts 中配置的超时时间
+func TestInstanceResourceTimeouts(t *testing.T) {
+	instance := map[string]any{
+		"id":            "i-12345678",
+		"image_id":      "ami-12345678",
+		"instance_type": "m1.small",
+		"subnet_id":     "subnet-12345678",
+	}
+	resized := map[string]any{
+		"id":            "i-12345678",
+		"image_id":      "ami-12345678",
+		"instance_type": "m1.large",
+		"subnet_id":     "subnet-12345678",
+	}
+
+	testResourceTimeouts(t, NewInstanceResource, []testTimeoutCase{
+		{
+			name: "create",
+			plan: map[string]any{
+				"image_id":      "ami-12345678",
+				"instance_type": "m1.small",
+				"subnet_id":     "subnet-12345678",
+			},
+		},
+		{
+			name:  "update",
+			state: instance,
+			plan:  resized,
+		},
+		{
+			name:  "delete",
+			state: instance,
+		},
+	})
+}
+
+// TestVpcResourceTimeouts 测试 VPC 的创建、更新和删除使用 timeouts 中配置的超时时间
+func TestVpcResourceTimeouts(t *testing.T) {
+	vpc := map[string]any{
+		"id":                   "vpc-12345678",
+		"cidr_block":           "10.0.0.0/16",
+		"enable_dns_support":   true,
+		"enable_dns_hostnames": false,
+	}
+	updated := map[string]any{
+		"id":                   "vpc-12345678",
+		"cidr_block":           "10.0.0.0/16",
+		"enable_dns_support":   true,
+		"enable_dns_hostnames": true,
+	}
+
+	testResourceTimeouts(t, NewVpcResource, []testTimeoutCase{
+		{
+			name: "create",
+			plan: map[string]any{
+				"cidr_block": "10.0.0.0/16",
+			},
+		},
+		{
+			name:  "update",
+			state: vpc,
+			plan:  updated,
+		},
+		{
+			name:  "delete",
+			state: vpc,
+		},
+	})
+}
+
+// TestSubnetResourceTimeouts 测试子网的创建、更新和删除使用 timeouts 中配置的超时时间
+func TestSubnetResourceTimeouts(t *testing.T) {
+	subnet := map[string]any{
+		"id":                      "subnet-12345678",
+		"vpc_id":                  "vpc-12345678",
+		"cidr_block":              "10.0.1.0/24",
+		"map_public_ip_on_launch": false,
+	}
+	updated := map[string]any{
+		"id":                      "subnet-12345678",
+		"vpc_id":                  "vpc-12345678",
+		"cidr_block":              "10.0.1.0/24",
+		"map_public_ip_on_launch": true,
+	}
+
+	testResourceTimeouts(t, NewSubnetResource, []testTimeoutCase{
+		{
+			name: "create",
+			plan: map[string]any{
+				"vpc_id":     "vpc-12345678",
+				"cidr_block": "10.0.1.0/24",
+			},
+		},
+		{
+			name:  "update",
+			state: subnet,
+			plan:  updated,
+		},
+		{
+			name:  "delete",
+			state: subnet,
+		},
+	})
+}
+
+// TestSecurityGroupResourceTimeouts 测试安全组的创建、更新和删除使用 timeouts 中配置的超时时间
+func TestSecurityGroupResourceTimeouts(t *testing.T) {
+	securityGroup := map[string]any{
+		"id":     "sg-12345678",
+		"name":   "test",
+		"vpc_id": "vpc-12345678",
+	}
+	tagged := map[string]any{
+		"id":       "sg-12345678",
+		"name":     "test",
+		"vpc_id":   "vpc-12345678",
+		"tags_all": map[string]string{"env": "test"},
+	}
+
+	testResourceTimeouts(t, NewSecurityGroupResource, []testTimeoutCase{
+		{
+			name: "create",
+			plan: map[string]any{
+				"name":   "test",
+				"vpc_id": "vpc-12345678",
+			},
+		},
+		{
+			name:  "update",
+			state: securityGroup,
+			plan:  tagged,
+		},
+		{
+			name:  "delete",
+			state: securityGroup,
+		},
+	})
+}
+
+// TestSecurityGroupRuleResourceTimeouts 测试安全组规则的创建和删除使用 timeouts 中配置的超时时间
+func TestSecurityGroupRuleResourceTimeouts(t *testing.T) {
+	rule := map[string]any{
+		"type":              "ingress",
+		"security_group_id": "sg-12345678",
+		"protocol":          "tcp",
+		"from_port":         int64(22),
+		"to_port":           int64(22),
+		"cidr_blocks":       []string{"10.0.0.0/16"},
+	}
+	created := map[string]any{
+		"id":                "sgrule-12345678",
+		"type":              "ingress",
+		"security_group_id": "sg-12345678",
+		"protocol":          "tcp",
+		"from_port":         int64(22),
+		"to_port":           int64(22),
+		"cidr_blocks":       []string{"10.0.0.0/16"},
+	}
+
+	testResourceTimeouts(t, NewSecurityGroupRuleResource, []testTimeoutCase{
+		{
+			name: "create",
+			plan: rule,
+		},
+		{
+			name:  "delete",
+			state: created,
+		},
+	})
+}
+
+// TestKeyPairResourceTimeouts 测试密钥对的创建和删除使用 timeouts 中配置的超时时间
+func TestKeyPairResourceTimeouts(t *testing.T) {
+	testResourceTimeouts(t, NewKeyPairResource, []testTimeoutCase{
+		{
+			name: "create",
+			plan: map[string]any{
+				"key_name": "test",
+			},
+		},
+		{
+			name: "import",
+			plan: map[string]any{
+				"key_name":   "test",
+				"public_key": "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGq7ZXN0a2V5bWF0ZXJpYWxmb3J0ZXN0aW5nb25seQ test",
+			},
+		},
+		{
+			name: "delete",
+			state: map[string]any{
+				"id":       "test",
+				"key_name": "test",
+			},
+		},
+	})
+}
+
+// TestEipResourceTimeouts 测试弹性 IP 的创建、更新和删除使用 timeouts 中配置的超时时间
+func TestEipResourceTimeouts(t *testing.T) {
+	eip := map[string]any{
+		"id":            "eipalloc-12345678",
+		"allocation_id": "eipalloc-12345678",
+		"domain":        "vpc",
+	}
+	tagged := map[string]any{
+		"id":            "eipalloc-12345678",
+		"allocation_id": "eipalloc-12345678",
+		"domain":        "vpc",
+		"tags_all":      map[string]string{"env": "test"},
+	}
+
+	testResourceTimeouts(t, NewEipResource, []testTimeoutCase{
+		{
+			name: "create",
+			plan: map[string]any{
+				"domain": "vpc",
+			},
+		},
+		{
+			name:  "update",
+			state: eip,
+			plan:  tagged,
+		},
+		{
+			name:  "delete",
+			state: eip,
+		},
+	})
+}
+
+// TestEipAssociationResourceTimeouts 测试弹性 IP 绑定的创建、更新和删除使用 timeouts 中配置的超时时间
+func TestEipAssociationResourceTimeouts(t *testing.T) {
+	association := map[string]any{
+		"id":            "eipassoc-12345678",
+		"allocation_id": "eipalloc-12345678",
+		"instance_id":   "i-12345678",
+	}
+	reassociated := map[string]any{
+		"id":            "eipassoc-12345678",
+		"allocation_id": "eipalloc-12345678",
+		"instance_id":   "i-87654321",
+	}
+
+	testResourceTimeouts(t, NewEipAssociationResource, []testTimeoutCase{
+		{
+			name: "create",
+			plan: map[string]any{
+				"allocation_id": "eipalloc-12345678",
+				"instance_id":   "i-12345678",
+			},
+		},
+		{
+			name:  "update",
+			state: association,
+			plan:  reassociated,
+		},
+		{
+			name:  "delete",
+			state: association,
+		},
+	})
+}
@@ -0,0 +1,635 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package ec2
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/mulei1288/terraform-provider-bingocloud/internal/conns"
+	tftags "github.com/mulei1288/terraform-provider-bingocloud/internal/tags"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/awserr"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/service/ec2"
+)
+
+// 确保实现了必需的接口
+var _ resource.Resource = &VpcResource{}
+var _ resource.ResourceWithImportState = &VpcResource{}
+var _ resource.ResourceWithModifyPlan = &VpcResource{}
+var _ resource.ResourceWithValidateConfig = &VpcResource{}
+
+// 默认超时时间
+const (
+	vpcCreateTimeout = 10 * time.Minute
+	vpcUpdateTimeout = 10 * time.Minute
+	vpcDeleteTimeout = 10 * time.Minute
+)
+
+// VPC 相关的错误码
+const (
+	errCodeInvalidVpcIDNotFound = "InvalidVpcID.NotFound"
+	errCodeDependencyViolation  = "DependencyViolation"
+)
+
+// VpcResource 定义 VPC 资源实现
+type VpcResource struct {
+	client *conns.BingoCloudClient
+}
+
+// VpcResourceModel 描述 VPC 资源数据模型
+type VpcResourceModel struct {
+	// 必需参数
+	CidrBlock types.String `tfsdk:"cidr_block"`
+
+	// 可选参数
+	EnableDnsSupport   types.Bool `tfsdk:"enable_dns_support"`
+	EnableDnsHostnames types.Bool `tfsdk:"enable_dns_hostnames"`
+	Tags               types.Map  `tfsdk:"tags"`
+
+	// 计算属性
+	ID                     types.String `tfsdk:"id"`
+	TagsAll                types.Map    `tfsdk:"tags_all"`
+	DefaultSecurityGroupID types.String `tfsdk:"default_security_group_id"`
+	MainRouteTableID       types.String `tfsdk:"main_route_table_id"`
+
+	// 超时配置
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// NewVpcResource 创建新的 VPC 资源
+func NewVpcResource() resource.Resource {
+	return &VpcResource{}
+}
+
+// Metadata 返回资源类型名称
+func (r *VpcResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vpc"
+}
+
+// Configure 配置资源，接收 Provider 传递的客户端
+func (r *VpcResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*conns.BingoCloudClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"意外的资源配置类型",
+			fmt.Sprintf("期望 *conns.BingoCloudClient，得到: %T。请向 provider 开发者报告此问题。", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+// Schema 定义资源的属性架构
+func (r *VpcResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "管理 BingoCloud VPC（虚拟私有网络）",
+
+		Attributes: map[string]schema.Attribute{
+			// 必需参数
+			"cidr_block": schema.StringAttribute{
+				MarkdownDescription: "VPC 的 IPv4 网段（如 10.0.0.0/16），修改时重建 VPC",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			// 可选参数
+			"enable_dns_support": schema.BoolAttribute{
+				MarkdownDescription: "是否启用 VPC 内的 DNS 解析，默认 true",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"enable_dns_hostnames": schema.BoolAttribute{
+				MarkdownDescription: "是否为 VPC 内的实例分配 DNS 主机名，需要同时启用 `enable_dns_support`，默认 false",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"tags": tftags.TagsAttribute(),
+
+			// 计算属性（只读）
+			"id": schema.StringAttribute{
+				MarkdownDescription: "VPC ID",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tags_all": tftags.TagsAllAttribute(),
+			"default_security_group_id": schema.StringAttribute{
+				MarkdownDescription: "创建 VPC 时自动创建的默认安全组 ID",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"main_route_table_id": schema.StringAttribute{
+				MarkdownDescription: "VPC 的主路由表 ID，未显式关联路由表的子网使用该路由表",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// Create 创建 VPC
+func (r *VpcResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan VpcResourceModel
+
+	// 读取 Terraform 计划数据
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, vpcCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	conn := r.client.EC2Client()
+
+	tflog.Debug(ctx, "创建 BingoCloud VPC", map[string]interface{}{
+		"cidr_block": plan.CidrBlock.ValueString(),
+	})
+
+	result, err := conn.CreateVpcWithContext(ctx, &ec2.CreateVpcInput{
+		CidrBlock: aws.String(plan.CidrBlock.ValueString()),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"创建 VPC 失败",
+			"无法创建 VPC: "+err.Error(),
+		)
+		return
+	}
+	if result.Vpc == nil || result.Vpc.VpcId == nil {
+		resp.Diagnostics.AddError(
+			"创建 VPC 失败",
+			"API 返回空的 VPC 信息",
+		)
+		return
+	}
+
+	vpcID := aws.StringValue(result.Vpc.VpcId)
+	plan.ID = types.StringValue(vpcID)
+
+	// 立即保存 VPC ID，后续步骤失败时资源会被标记为 tainted
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), plan.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("timeouts"), plan.Timeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := conn.WaitUntilVpcAvailableWithContext(ctx, &ec2.DescribeVpcsInput{
+		VpcIds: []*string{aws.String(vpcID)},
+	}, waiterOptions(createTimeout)...); err != nil {
+		resp.Diagnostics.AddError(
+			"等待 VPC 可用失败",
+			"VPC "+vpcID+" 创建成功但未能进入可用状态: "+waitErrorDetail(ctx, "创建", createTimeout, err, vpcLastState(conn, vpcID)),
+		)
+		return
+	}
+
+	// 标签：创建后通过 CreateTags 添加
+	tags, diags := tagsMapValue(ctx, plan.TagsAll)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if err := updateTags(ctx, conn, vpcID, nil, tags); err != nil {
+		resp.Diagnostics.AddError(
+			"设置 VPC 标签失败",
+			"无法为 VPC "+vpcID+" 设置标签: "+err.Error(),
+		)
+		return
+	}
+
+	// DNS 属性：与 API 默认值（启用 DNS 解析、不分配主机名）不同时才修改
+	resp.Diagnostics.Append(updateVpcDnsAttributes(ctx, conn, vpcID, plan, VpcResourceModel{
+		EnableDnsSupport:   types.BoolValue(true),
+		EnableDnsHostnames: types.BoolValue(false),
+	})...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	vpc, err := findVpcByID(ctx, conn, vpcID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"读取 VPC 详情失败",
+			"VPC 创建成功但无法读取详细信息: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(flattenVpcComputed(ctx, conn, vpc, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "创建 VPC 成功")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read 读取 VPC 状态
+func (r *VpcResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state VpcResourceModel
+
+	// 读取 Terraform 状态数据
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn := r.client.EC2Client()
+	vpcID := state.ID.ValueString()
+
+	vpc, err := findVpcByID(ctx, conn, vpcID)
+	if err != nil {
+		if isErrorCode(err, errCodeInvalidVpcIDNotFound) {
+			// VPC 不存在，从状态中移除
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"读取 VPC 失败",
+			"无法读取 VPC "+vpcID+": "+err.Error(),
+		)
+		return
+	}
+
+	state.CidrBlock = types.StringValue(aws.StringValue(vpc.CidrBlock))
+
+	// DNS 属性
+	for _, a := range []struct {
+		attribute string
+		value     *types.Bool
+	}{
+		{ec2.VpcAttributeNameEnableDnsSupport, &state.EnableDnsSupport},
+		{ec2.VpcAttributeNameEnableDnsHostnames, &state.EnableDnsHostnames},
+	} {
+		enabled, err := findVpcAttribute(ctx, conn, vpcID, a.attribute)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"读取 VPC 属性失败",
+				"无法读取 VPC "+vpcID+" 的 "+a.attribute+" 属性: "+err.Error(),
+			)
+			return
+		}
+		*a.value = types.BoolValue(enabled)
+	}
+
+	resp.Diagnostics.Append(flattenVpcComputed(ctx, conn, vpc, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// 标签：按 provider 的默认标签和忽略标签配置拆分到 tags 和 tags_all
+	tagsValue, tagsAllValue, diags := tftags.Flatten(ctx, tagsToMap(vpc.Tags), state.Tags, r.client.DefaultTagsConfig, r.client.IgnoreTagsConfig)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	state.Tags = tagsValue
+	state.TagsAll = tagsAllValue
+
+	// 保存更新后的状态
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update 更新 VPC 的 DNS 属性和标签
+func (r *VpcResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state VpcResourceModel
+
+	// 读取计划数据和当前状态
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, vpcUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	conn := r.client.EC2Client()
+	vpcID := state.ID.ValueString()
+
+	resp.Diagnostics.Append(updateVpcDnsAttributes(ctx, conn, vpcID, plan, state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// 标签：按包含默认标签的 tags_all 比较
+	if !plan.TagsAll.Equal(state.TagsAll) {
+		oldTags, diags := tagsMapValue(ctx, state.TagsAll)
+		resp.Diagnostics.Append(diags...)
+		newTags, diags := tagsMapValue(ctx, plan.TagsAll)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if err := updateTags(ctx, conn, vpcID, oldTags, newTags); err != nil {
+			resp.Diagnostics.AddError(
+				"更新 VPC 标签失败",
+				"无法更新 VPC "+vpcID+" 的标签: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	tflog.Trace(ctx, "更新 VPC 成功")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete 删除 VPC，VPC 中的子网等资源正在删除时等待其删除完成后重试
+func (r *VpcResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state VpcResourceModel
+
+	// 读取 Terraform 状态数据
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, vpcDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	conn := r.client.EC2Client()
+	vpcID := state.ID.ValueString()
+
+	tflog.Debug(ctx, "删除 BingoCloud VPC", map[string]interface{}{
+		"vpc_id": vpcID,
+	})
+
+	err := retryWhenErrorCode(ctx, errCodeDependencyViolation, func() error {
+		_, err := conn.DeleteVpcWithContext(ctx, &ec2.DeleteVpcInput{
+			VpcId: aws.String(vpcID),
+		})
+		return err
+	})
+	if err != nil && !isErrorCode(err, errCodeInvalidVpcIDNotFound) {
+		resp.Diagnostics.AddError(
+			"删除 VPC 失败",
+			"无法删除 VPC "+vpcID+": "+waitErrorDetail(ctx, "删除", deleteTimeout, err, vpcLastState(conn, vpcID)),
+		)
+		return
+	}
+
+	tflog.Trace(ctx, "删除 VPC 成功")
+}
+
+// ValidateConfig 校验配置：cidr_block 必须是有效的网段，分配 DNS 主机名需要启用 DNS 解析
+func (r *VpcResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config VpcResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateCidrBlock(config.CidrBlock, path.Root("cidr_block"))...)
+
+	if config.EnableDnsHostnames.ValueBool() && !config.EnableDnsSupport.IsNull() && !config.EnableDnsSupport.IsUnknown() && !config.EnableDnsSupport.ValueBool() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("enable_dns_hostnames"),
+			"无效的 DNS 配置",
+			"enable_dns_hostnames 为 true 时 enable_dns_support 不能为 false",
+		)
+	}
+}
+
+// ModifyPlan 调整计划：计算 tags_all
+func (r *VpcResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// 销毁时无需调整
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	// 合并 provider 的默认标签
+	if r.client != nil {
+		resp.Diagnostics.Append(tftags.ModifyPlan(ctx, r.client.DefaultTagsConfig, r.client.IgnoreTagsConfig, &resp.Plan)...)
+	}
+}
+
+// ImportState 支持通过 VPC ID 导入资源
+func (r *VpcResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// validateCidrBlock 校验网段格式，值未知时跳过
+func validateCidrBlock(value types.String, p path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if value.IsNull() || value.IsUnknown() {
+		return diags
+	}
+
+	ip, ipNet, err := net.ParseCIDR(value.ValueString())
+	if err != nil || ip.To4() == nil {
+		diags.AddAttributeError(p, "无效的网段", "必须是有效的 IPv4 网段（如 10.0.0.0/16）: "+value.ValueString())
+		return diags
+	}
+	if !ip.Equal(ipNet.IP) {
+		diags.AddAttributeError(p, "无效的网段", fmt.Sprintf("%s 不是网段的起始地址，应为 %s", value.ValueString(), ipNet.String()))
+	}
+
+	return diags
+}
+
+// findVpcByID 根据 VPC ID 查询 VPC，VPC 不存在时返回 InvalidVpcID.NotFound 错误
+func findVpcByID(ctx context.Context, conn *ec2.EC2, id string) (*ec2.Vpc, error) {
+	result, err := conn.DescribeVpcsWithContext(ctx, &ec2.DescribeVpcsInput{
+		VpcIds: []*string{aws.String(id)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Vpcs) == 0 || result.Vpcs[0] == nil {
+		return nil, awserr.New(errCodeInvalidVpcIDNotFound, "VPC "+id+" 不存在", nil)
+	}
+
+	return result.Vpcs[0], nil
+}
+
+// vpcLastState 返回查询 VPC 最后状态的函数，查询失败时返回 unknown
+func vpcLastState(conn *ec2.EC2, id string) func(context.Context) string {
+	return func(ctx context.Context) string {
+		vpc, err := findVpcByID(ctx, conn, id)
+		if err != nil {
+			return "unknown"
+		}
+		return aws.StringValue(vpc.State)
+	}
+}
+
+// findVpcAttribute 查询 VPC 的 DNS 属性
+func findVpcAttribute(ctx context.Context, conn *ec2.EC2, id, attribute string) (bool, error) {
+	output, err := conn.DescribeVpcAttributeWithContext(ctx, &ec2.DescribeVpcAttributeInput{
+		VpcId:     aws.String(id),
+		Attribute: aws.String(attribute),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	switch attribute {
+	case ec2.VpcAttributeNameEnableDnsSupport:
+		if output.EnableDnsSupport != nil {
+			return aws.BoolValue(output.EnableDnsSupport.Value), nil
+		}
+	case ec2.VpcAttributeNameEnableDnsHostnames:
+		if output.EnableDnsHostnames != nil {
+			return aws.BoolValue(output.EnableDnsHostnames.Value), nil
+		}
+	}
+	return false, nil
+}
+
+// modifyVpcAttribute 修改 VPC 的 DNS 属性，每次请求只能修改一个属性
+func modifyVpcAttribute(ctx context.Context, conn *ec2.EC2, id, attribute string, value bool) error {
+	input := &ec2.ModifyVpcAttributeInput{
+		VpcId: aws.String(id),
+	}
+	switch attribute {
+	case ec2.VpcAttributeNameEnableDnsSupport:
+		input.EnableDnsSupport = &ec2.AttributeBooleanValue{Value: aws.Bool(value)}
+	case ec2.VpcAttributeNameEnableDnsHostnames:
+		input.EnableDnsHostnames = &ec2.AttributeBooleanValue{Value: aws.Bool(value)}
+	}
+
+	tflog.Debug(ctx, "修改 VPC 属性", map[string]interface{}{
+		"vpc_id":    id,
+		"attribute": attribute,
+		"value":     value,
+	})
+
+	_, err := conn.ModifyVpcAttributeWithContext(ctx, input)
+	return err
+}
+
+// updateVpcDnsAttributes 修改发生变化的 DNS 属性
+// 分配主机名依赖 DNS 解析：启用 DNS 解析时先修改 enable_dns_support，关闭时先修改 enable_dns_hostnames
+func updateVpcDnsAttributes(ctx context.Context, conn *ec2.EC2, id string, plan, state VpcResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	changes := []struct {
+		attribute   string
+		plan, state types.Bool
+	}{
+		{ec2.VpcAttributeNameEnableDnsSupport, plan.EnableDnsSupport, state.EnableDnsSupport},
+		{ec2.VpcAttributeNameEnableDnsHostnames, plan.EnableDnsHostnames, state.EnableDnsHostnames},
+	}
+	if !plan.EnableDnsSupport.ValueBool() {
+		changes[0], changes[1] = changes[1], changes[0]
+	}
+
+	for _, c := range changes {
+		if c.plan.IsUnknown() || c.plan.Equal(c.state) {
+			continue
+		}
+		if err := modifyVpcAttribute(ctx, conn, id, c.attribute, c.plan.ValueBool()); err != nil {
+			diags.AddError(
+				"修改 VPC 属性失败",
+				"无法修改 VPC "+id+" 的 "+c.attribute+" 属性: "+err.Error(),
+			)
+			return diags
+		}
+	}
+
+	return diags
+}
+
+// flattenVpcComputed 查询 VPC 的默认安全组和主路由表并写入模型
+func flattenVpcComputed(ctx context.Context, conn *ec2.EC2, vpc *ec2.Vpc, model *VpcResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	vpcID := aws.StringValue(vpc.VpcId)
+	vpcFilter := &ec2.Filter{Name: aws.String("vpc-id"), Values: []*string{vpc.VpcId}}
+
+	sgResult, err := conn.DescribeSecurityGroupsWithContext(ctx, &ec2.DescribeSecurityGroupsInput{
+		Filters: []*ec2.Filter{
+			vpcFilter,
+			{Name: aws.String("group-name"), Values: []*string{aws.String("default")}},
+		},
+	})
+	if err != nil {
+		diags.AddError(
+			"读取 VPC 默认安全组失败",
+			"无法查询 VPC "+vpcID+" 的默认安全组: "+err.Error(),
+		)
+		return diags
+	}
+	model.DefaultSecurityGroupID = types.StringNull()
+	if len(sgResult.SecurityGroups) > 0 {
+		model.DefaultSecurityGroupID = types.StringValue(aws.StringValue(sgResult.SecurityGroups[0].GroupId))
+	}
+
+	rtResult, err := conn.DescribeRouteTablesWithContext(ctx, &ec2.DescribeRouteTablesInput{
+		Filters: []*ec2.Filter{
+			vpcFilter,
+			{Name: aws.String("association.main"), Values: []*string{aws.String("true")}},
+		},
+	})
+	if err != nil {
+		diags.AddError(
+			"读取 VPC 主路由表失败",
+			"无法查询 VPC "+vpcID+" 的主路由表: "+err.Error(),
+		)
+		return diags
+	}
+	model.MainRouteTableID = types.StringNull()
+	if len(rtResult.RouteTables) > 0 {
+		model.MainRouteTableID = types.StringValue(aws.StringValue(rtResult.RouteTables[0].RouteTableId))
+	}
+
+	return diags
+}
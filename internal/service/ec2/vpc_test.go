@@ -0,0 +1,64 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package ec2_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/mulei1288/terraform-provider-bingocloud/internal/acctest"
+)
+
+// testAccVpcConfig 生成 VPC 资源的测试配置
+func testAccVpcConfig(name string, enableDnsHostnames bool) string {
+	return acctest.ProviderConfig() + fmt.Sprintf(`
+resource "bingocloud_vpc" "test" {
+  cidr_block           = "10.10.0.0/16"
+  enable_dns_hostnames = %[2]t
+
+  tags = {
+    Name        = %[1]q
+    Environment = "test"
+  }
+}
+`, name, enableDnsHostnames)
+}
+
+// TestAccVpcResource_basic 测试 VPC 资源的创建、原地更新和导入
+func TestAccVpcResource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// 创建和读取测试
+			{
+				Config: testAccVpcConfig("test-vpc", false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("bingocloud_vpc.test", "cidr_block", "10.10.0.0/16"),
+					resource.TestCheckResourceAttr("bingocloud_vpc.test", "enable_dns_support", "true"),
+					resource.TestCheckResourceAttr("bingocloud_vpc.test", "enable_dns_hostnames", "false"),
+					resource.TestCheckResourceAttr("bingocloud_vpc.test", "tags.Name", "test-vpc"),
+					resource.TestCheckResourceAttrSet("bingocloud_vpc.test", "id"),
+					resource.TestCheckResourceAttrSet("bingocloud_vpc.test", "default_security_group_id"),
+					resource.TestCheckResourceAttrSet("bingocloud_vpc.test", "main_route_table_id"),
+				),
+			},
+			// 原地修改 DNS 主机名和标签
+			{
+				Config: testAccVpcConfig("test-vpc-updated", true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("bingocloud_vpc.test", "enable_dns_hostnames", "true"),
+					resource.TestCheckResourceAttr("bingocloud_vpc.test", "tags.Name", "test-vpc-updated"),
+				),
+			},
+			// 导入状态测试
+			{
+				ResourceName:      "bingocloud_vpc.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
@@ -0,0 +1,198 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package ec2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/awserr"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/aws/request"
+	"gitlab.bingosoft.net/bingokube/aws-sdk-go/service/ec2"
+)
+
+// instanceWaiterDelay 轮询实例状态的间隔
+const instanceWaiterDelay = 5 * time.Second
+
+// lastStateTimeout 超时后查询资源最后状态所用的时间上限
+const lastStateTimeout = 30 * time.Second
+
+// waiterMaxAttempts 根据超时时间计算轮询次数
+// 轮询总时长略大于超时时间，确保等待总是由 context 截止时间结束
+func waiterMaxAttempts(timeout, delay time.Duration) int {
+	return int(timeout/delay) + 1
+}
+
+// waiterOptions 返回按超时时间配置轮询间隔和次数的 waiter 选项
+func waiterOptions(timeout time.Duration) []request.WaiterOption {
+	return []request.WaiterOption{
+		request.WithWaiterDelay(request.ConstantWaiterDelay(instanceWaiterDelay)),
+		request.WithWaiterMaxAttempts(waiterMaxAttempts(timeout, instanceWaiterDelay)),
+	}
+}
+
+// waitErrorDetail 返回等待失败的错误详情
+// 若 context 已超过截止时间，则注明超时的阶段以及资源最后一次观察到的状态
+func waitErrorDetail(ctx context.Context, phase string, timeout time.Duration, err error, lastState func(context.Context) string) string {
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return err.Error()
+	}
+
+	// 原 context 已过期，使用独立的短时 context 查询最后状态
+	stateCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), lastStateTimeout)
+	defer cancel()
+
+	return fmt.Sprintf("%s阶段超时（%s），最后状态: %s: %s", phase, timeout, lastState(stateCtx), err.Error())
+}
+
+// instanceLastState 返回查询实例最后状态的函数，查询失败时返回 unknown
+func instanceLastState(conn *ec2.EC2, id string) func(context.Context) string {
+	return func(ctx context.Context) string {
+		instance, err := findInstanceByID(ctx, conn, id)
+		if err != nil || instance.State == nil {
+			return "unknown"
+		}
+		return aws.StringValue(instance.State.Name)
+	}
+}
+
+// instancesLastState 返回查询多个实例最后状态的函数，格式为 "实例ID=状态"
+func instancesLastState(conn *ec2.EC2, ids []string) func(context.Context) string {
+	return func(ctx context.Context) string {
+		instances, err := findInstancesByIDs(ctx, conn, ids)
+		if err != nil {
+			return "unknown"
+		}
+
+		states := make([]string, 0, len(ids))
+		for _, id := range ids {
+			state := "terminated"
+			if instance, ok := instances[id]; ok && instance.State != nil {
+				state = aws.StringValue(instance.State.Name)
+			}
+			states = append(states, id+"="+state)
+		}
+		return strings.Join(states, ", ")
+	}
+}
+
+// waitInstancesRunning 等待全部实例进入 running 状态
+func waitInstancesRunning(ctx context.Context, conn *ec2.EC2, ids []string, timeout time.Duration) error {
+	return conn.WaitUntilInstanceRunningWithContext(ctx, &ec2.DescribeInstancesInput{
+		InstanceIds: aws.StringSlice(ids),
+	}, waiterOptions(timeout)...)
+}
+
+// waitInstanceTerminated 等待实例进入 terminated 状态
+// 与 SDK 的 WaitUntilInstanceTerminated 不同，实例已不存在（InvalidInstanceID.NotFound
+// 或返回空列表）时同样视为删除成功
+func waitInstanceTerminated(ctx context.Context, conn *ec2.EC2, id string, timeout time.Duration) error {
+	w := request.Waiter{
+		Name: "WaitUntilInstanceTerminated",
+		Acceptors: []request.WaiterAcceptor{
+			{
+				State:   request.SuccessWaiterState,
+				Matcher: request.PathAllWaiterMatch, Argument: "Reservations[].Instances[].State.Name",
+				Expected: "terminated",
+			},
+			{
+				State:   request.SuccessWaiterState,
+				Matcher: request.PathWaiterMatch, Argument: "length(Reservations[]) == `0`",
+				Expected: true,
+			},
+			{
+				State:    request.SuccessWaiterState,
+				Matcher:  request.ErrorWaiterMatch,
+				Expected: "InvalidInstanceID.NotFound",
+			},
+			{
+				State:   request.FailureWaiterState,
+				Matcher: request.PathAnyWaiterMatch, Argument: "Reservations[].Instances[].State.Name",
+				Expected: "pending",
+			},
+			{
+				State:   request.FailureWaiterState,
+				Matcher: request.PathAnyWaiterMatch, Argument: "Reservations[].Instances[].State.Name",
+				Expected: "stopping",
+			},
+		},
+		Logger: conn.Config.Logger,
+		NewRequest: func(opts []request.Option) (*request.Request, error) {
+			req, _ := conn.DescribeInstancesRequest(&ec2.DescribeInstancesInput{
+				InstanceIds: []*string{aws.String(id)},
+			})
+			req.SetContext(ctx)
+			req.ApplyOptions(opts...)
+			return req, nil
+		},
+	}
+	w.ApplyOptions(waiterOptions(timeout)...)
+
+	return w.WaitWithContext(ctx)
+}
+
+// retryDelay 资源处于依赖关系变化中时重试操作的间隔
+const retryDelay = 5 * time.Second
+
+// retryWhenErrorCode 在 fn 返回指定错误码时按固定间隔重试，直到成功、返回其它错误或 context 结束
+// 用于删除仍被其它正在删除的资源依赖的资源，例如 VPC 中的子网尚未完全删除时删除 VPC
+func retryWhenErrorCode(ctx context.Context, code string, fn func() error) error {
+	for {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		if !isErrorCode(err, code) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(retryDelay):
+		}
+	}
+}
+
+// isErrorCode 判断错误是否为指定错误码的 API 错误
+func isErrorCode(err error, code string) bool {
+	var awsErr awserr.Error
+	return errors.As(err, &awsErr) && awsErr.Code() == code
+}
+
+// waitSubnetDeleted 等待子网删除完成，子网不存在（InvalidSubnetID.NotFound 或返回空列表）时视为删除成功
+func waitSubnetDeleted(ctx context.Context, conn *ec2.EC2, id string, timeout time.Duration) error {
+	w := request.Waiter{
+		Name: "WaitUntilSubnetDeleted",
+		Acceptors: []request.WaiterAcceptor{
+			{
+				State:   request.SuccessWaiterState,
+				Matcher: request.PathWaiterMatch, Argument: "length(Subnets[]) == `0`",
+				Expected: true,
+			},
+			{
+				State:    request.SuccessWaiterState,
+				Matcher:  request.ErrorWaiterMatch,
+				Expected: errCodeInvalidSubnetIDNotFound,
+			},
+		},
+		Logger: conn.Config.Logger,
+		NewRequest: func(opts []request.Option) (*request.Request, error) {
+			req, _ := conn.DescribeSubnetsRequest(&ec2.DescribeSubnetsInput{
+				SubnetIds: []*string{aws.String(id)},
+			})
+			req.SetContext(ctx)
+			req.ApplyOptions(opts...)
+			return req, nil
+		},
+	}
+	w.ApplyOptions(waiterOptions(timeout)...)
+
+	return w.WaitWithContext(ctx)
+}
@@ -0,0 +1,122 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package tags
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// TagsAttribute 返回资源 tags 属性的架构
+func TagsAttribute() schema.MapAttribute {
+	return schema.MapAttribute{
+		MarkdownDescription: "资源标签，会与 provider 的 `default_tags` 合并，同名的键以这里为准",
+		ElementType:         types.StringType,
+		Optional:            true,
+	}
+}
+
+// TagsAllAttribute 返回资源 tags_all 属性的架构
+func TagsAllAttribute() schema.MapAttribute {
+	return schema.MapAttribute{
+		MarkdownDescription: "资源的全部标签，包括 provider `default_tags` 中的标签，不包括 `ignore_tags` 忽略的标签",
+		ElementType:         types.StringType,
+		Computed:            true,
+	}
+}
+
+// ModifyPlan 在计划阶段计算 tags_all：默认标签与资源的 tags 合并后去除忽略的键和 reservedKeys
+// tags 未知时 tags_all 也未知
+func ModifyPlan(ctx context.Context, defaultConfig *DefaultConfig, ignoreConfig *IgnoreConfig, plan *tfsdk.Plan, reservedKeys ...string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var tags types.Map
+	diags.Append(plan.GetAttribute(ctx, path.Root("tags"), &tags)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	if tags.IsUnknown() {
+		diags.Append(plan.SetAttribute(ctx, path.Root("tags_all"), types.MapUnknown(types.StringType))...)
+		return diags
+	}
+
+	configured := make(map[string]string)
+	if !tags.IsNull() {
+		diags.Append(tags.ElementsAs(ctx, &configured, false)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	tagsAll, d := types.MapValueFrom(ctx, types.StringType, RemoveKeys(ignoreConfig.IgnoreTags(defaultConfig.MergeTags(configured)), reservedKeys...))
+	diags.Append(d...)
+	if diags.HasError() {
+		return diags
+	}
+
+	diags.Append(plan.SetAttribute(ctx, path.Root("tags_all"), tagsAll)...)
+	return diags
+}
+
+// Flatten 将 API 返回的标签转换为 tags 和 tags_all
+// tags_all 为去除忽略键和 reservedKeys 之后的全部标签，tags 再去除来自默认标签的键；
+// prior 为状态中原有的 tags，其中显式配置的键始终保留在 tags 中，原来为空且没有标签时保持为空
+func Flatten(ctx context.Context, apiTags map[string]string, prior types.Map, defaultConfig *DefaultConfig, ignoreConfig *IgnoreConfig, reservedKeys ...string) (types.Map, types.Map, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	configured := make(map[string]string)
+	if !prior.IsNull() && !prior.IsUnknown() {
+		diags.Append(prior.ElementsAs(ctx, &configured, false)...)
+		if diags.HasError() {
+			return prior, types.MapNull(types.StringType), diags
+		}
+	}
+
+	all := RemoveKeys(ignoreConfig.IgnoreTags(apiTags), reservedKeys...)
+	resourceTags := defaultConfig.RemoveDefaultTags(all, configured)
+
+	tagsAll, d := types.MapValueFrom(ctx, types.StringType, all)
+	diags.Append(d...)
+
+	tags := types.MapNull(types.StringType)
+	if len(resourceTags) > 0 || !prior.IsNull() {
+		tags, d = types.MapValueFrom(ctx, types.StringType, resourceTags)
+		diags.Append(d...)
+	}
+
+	return tags, tagsAll, diags
+}
+
+// ValidateNameTag 校验 tags 中没有配置 Name 标签，Name 标签只能通过名称属性 nameAttribute 配置
+func ValidateNameTag(ctx context.Context, tags types.Map, nameAttribute string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if tags.IsNull() || tags.IsUnknown() {
+		return diags
+	}
+
+	if _, ok := tags.Elements()[NameKey]; ok {
+		diags.AddAttributeError(
+			path.Root("tags").AtMapKey(NameKey),
+			"Name 标签与 "+nameAttribute+" 冲突",
+			"Name 标签由 "+nameAttribute+" 管理，请从 tags 中删除 Name，改用 "+nameAttribute+" 设置名称",
+		)
+	}
+
+	return diags
+}
+
+// NameValue 返回 API 标签中 Name 标签的值，没有 Name 标签时返回空值
+func NameValue(apiTags map[string]string) types.String {
+	if name, ok := apiTags[NameKey]; ok {
+		return types.StringValue(name)
+	}
+	return types.StringNull()
+}
@@ -0,0 +1,95 @@
+// Copyright IBM Corp. 2021, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+// Package tags 提供各服务包共用的标签处理逻辑：provider 级别的默认标签和忽略标签
+package tags
+
+import (
+	"strings"
+)
+
+// NameKey Name 标签的键
+// 带有名称属性（如 instance_name）的资源由名称属性独占 Name 标签：tags 中不允许配置 Name，
+// 默认标签中的 Name 不会合并，tags 和 tags_all 中也不包含 Name
+const NameKey = "Name"
+
+// DefaultConfig provider 级别的默认标签配置，合并到每个支持标签的资源中
+type DefaultConfig struct {
+	Tags map[string]string
+}
+
+// IgnoreConfig provider 级别的忽略标签配置，匹配的标签不会被读取到状态中
+type IgnoreConfig struct {
+	Keys        []string
+	KeyPrefixes []string
+}
+
+// MergeTags 将资源标签合并到默认标签之上，同名的键以资源标签为准
+func (c *DefaultConfig) MergeTags(tags map[string]string) map[string]string {
+	result := make(map[string]string, len(tags))
+	if c != nil {
+		for k, v := range c.Tags {
+			result[k] = v
+		}
+	}
+	for k, v := range tags {
+		result[k] = v
+	}
+	return result
+}
+
+// RemoveDefaultTags 从完整标签中去除来自默认标签的键
+// 值与默认标签相同且未在 configured 中显式配置的键视为来自默认标签
+func (c *DefaultConfig) RemoveDefaultTags(tagsAll, configured map[string]string) map[string]string {
+	result := make(map[string]string, len(tagsAll))
+	for k, v := range tagsAll {
+		if _, ok := configured[k]; !ok && c != nil {
+			if dv, ok := c.Tags[k]; ok && dv == v {
+				continue
+			}
+		}
+		result[k] = v
+	}
+	return result
+}
+
+// Ignored 判断标签键是否需要忽略
+func (c *IgnoreConfig) Ignored(key string) bool {
+	if c == nil {
+		return false
+	}
+	for _, k := range c.Keys {
+		if key == k {
+			return true
+		}
+	}
+	for _, prefix := range c.KeyPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// IgnoreTags 返回去除忽略键之后的标签
+func (c *IgnoreConfig) IgnoreTags(tags map[string]string) map[string]string {
+	result := make(map[string]string, len(tags))
+	for k, v := range tags {
+		if !c.Ignored(k) {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// RemoveKeys 返回去除指定键之后的标签
+func RemoveKeys(tags map[string]string, keys ...string) map[string]string {
+	result := make(map[string]string, len(tags))
+	for k, v := range tags {
+		result[k] = v
+	}
+	for _, k := range keys {
+		delete(result, k)
+	}
+	return result
+}